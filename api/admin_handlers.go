@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"EmployeeMerchStore/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// ReverseTransfer обрабатывает POST /api/admin/transfers/{id}/reverse.
+// id - идентификатор записи 'transfer_out' из истории отправителя (/api/info).
+// Тело запроса (JSON):
+//   - reason (string) - обязательная причина отката
+//   - allowNegative (bool) - откатить, даже если баланс получателя уйдет в минус
+func (h *Handler) ReverseTransfer(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := h.adminIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	transferID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid transfer id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason        string `json:"reason"`
+		AllowNegative bool   `json:"allowNegative"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Reason == "" {
+		http.Error(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	reversal, err := h.LedgerService.ReverseTransfer(r.Context(), adminID, transferID, req.Reason, req.AllowNegative)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, repository.ErrTransferNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, repository.ErrTransferAlreadyReversed), errors.Is(err, repository.ErrRecipientFundsSpent):
+			status = http.StatusConflict
+		}
+		http.Error(w, "failed to reverse transfer: "+err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reversal)
+}
@@ -2,10 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
 	"EmployeeMerchStore/internal/service"
 	"github.com/gorilla/mux"
 )
@@ -14,16 +17,70 @@ type Handler struct {
 	UserService      *service.UserService
 	PurchasesService *service.PurchasesService
 	LedgerService    *service.LedgerService
+	MerchService     *service.MerchService
+	WishlistService  *service.WishlistService
+	OrderService     *service.OrderService
+	PromotionService *service.PromotionService
+	ImageService     *service.ImageService
+	HealthService    *service.HealthService
+	Logger           *slog.Logger
 }
 
-func NewHandler(userService *service.UserService, purchasesService *service.PurchasesService, ledgerService *service.LedgerService) *Handler {
+func NewHandler(userService *service.UserService, purchasesService *service.PurchasesService, ledgerService *service.LedgerService, merchService *service.MerchService, wishlistService *service.WishlistService, orderService *service.OrderService, promotionService *service.PromotionService, imageService *service.ImageService, healthService *service.HealthService, logger *slog.Logger) *Handler {
 	return &Handler{
 		UserService:      userService,
 		PurchasesService: purchasesService,
 		LedgerService:    ledgerService,
+		MerchService:     merchService,
+		WishlistService:  wishlistService,
+		OrderService:     orderService,
+		PromotionService: promotionService,
+		ImageService:     imageService,
+		HealthService:    healthService,
+		Logger:           logger,
 	}
 }
 
+// userIDFromRequest достает ID пользователя из заголовка Authorization.
+// Если токена нет или он невалиден, отвечает 401 и возвращает false.
+func (h *Handler) userIDFromRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "missing authorization header", http.StatusUnauthorized)
+		return "", false
+	}
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+		return "", false
+	}
+	userID, err := h.UserService.DecodeToken(parts[1])
+	if err != nil {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return "", false
+	}
+	return userID, true
+}
+
+// adminIDFromRequest работает как userIDFromRequest, но пускает только администраторов.
+func (h *Handler) adminIDFromRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return "", false
+	}
+	isAdmin, err := h.UserService.IsAdmin(r.Context(), userID)
+	if err != nil {
+		h.Logger.ErrorContext(r.Context(), "Failed to check admin rights", "user_id", userID, "error", err)
+		http.Error(w, "failed to check admin rights", http.StatusInternalServerError)
+		return "", false
+	}
+	if !isAdmin {
+		http.Error(w, "admin rights required", http.StatusForbidden)
+		return "", false
+	}
+	return userID, true
+}
+
 type Req struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -85,6 +142,7 @@ func (h *Handler) Auth(w http.ResponseWriter, r *http.Request) {
                 if strings.Contains(err.Error(), "user already exists") {
                     http.Error(w, "username already taken", http.StatusConflict)
                 } else {
+                    h.Logger.ErrorContext(r.Context(), "Failed to create user", "username", req.Username, "error", err)
                     http.Error(w, "failed to create user", http.StatusInternalServerError)
                 }
                 return
@@ -103,22 +161,21 @@ func (h *Handler) Auth(w http.ResponseWriter, r *http.Request) {
 }
 
 // Info обрабатывает GET /api/info.
-// Возвращает баланс, инвентарь и историю транзакций.
+// Возвращает баланс, инвентарь, историю транзакций и полученные подарки.
 func (h *Handler) Info(w http.ResponseWriter, r *http.Request) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "missing authorization header", http.StatusUnauthorized)
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+
+	// Получаем информацию пользователя
+	balance, inventory, received, sent, err := h.UserService.GetInfo(r.Context(), userID, h.PurchasesService, h.LedgerService)
+	if err != nil {
+		http.Error(w, "failed to get info: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	token := parts[1]
 
-	// Получаем информацию пользователя по токену
-	balance, inventory, received, sent, err := h.UserService.GetInfo(r.Context(), token, h.PurchasesService, h.LedgerService)
+	gifts, err := h.PurchasesService.GetReceivedGifts(r.Context(), userID)
 	if err != nil {
 		http.Error(w, "failed to get info: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -144,9 +201,11 @@ func (h *Handler) Info(w http.ResponseWriter, r *http.Request) {
 			Received []models.Ledger `json:"received"`
 			Sent     []models.Ledger `json:"sent"`
 		} `json:"coinHistory"`
+		Gifts       []models.Gift       `json:"gifts"`
 	}{
 		Coins:     balance,
 		Inventory: inv,
+		Gifts:     gifts,
 		CoinHistory: struct {
 			Received []models.Ledger `json:"received"`
 			Sent     []models.Ledger `json:"sent"`
@@ -162,27 +221,17 @@ func (h *Handler) Info(w http.ResponseWriter, r *http.Request) {
 
 // SendCoin обрабатывает POST /api/sendCoin.
 // Ожидает JSON с полями toUser (имя получателя) и amount (количество монет).
+// Если escrow = true, перевод ждет подтверждения получателя (см. AcceptTransfer).
 func (h *Handler) SendCoin(w http.ResponseWriter, r *http.Request) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "missing authorization header", http.StatusUnauthorized)
-		return
-	}
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		http.Error(w, "invalid authorization header", http.StatusUnauthorized)
-		return
-	}
-	token := parts[1]
-	senderID, err := h.UserService.DecodeToken(token)
-	if err != nil {
-		http.Error(w, "invalid token", http.StatusUnauthorized)
+	senderID, ok := h.userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
 
 	var req struct {
 		ToUser string  `json:"toUser"`
 		Amount int `json:"amount"`
+		Escrow bool `json:"escrow"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "invalid request body", http.StatusBadRequest)
@@ -193,6 +242,22 @@ func (h *Handler) SendCoin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Escrow {
+		transferID, err := h.LedgerService.SendMoneyEscrow(r.Context(), senderID, req.ToUser, req.Amount)
+		if err != nil {
+			http.Error(w, "failed to send coin: "+err.Error(), transferErrorStatus(err))
+			return
+		}
+
+		resp := struct {
+			Message    string `json:"message"`
+			TransferID int    `json:"transferId"`
+		}{Message: "Coin transfer is waiting for recipient acceptance", TransferID: transferID}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
 	// Выполняем перевод монет
 	if err := h.LedgerService.SendMoney(r.Context(), senderID, req.ToUser, req.Amount); err != nil {
 		http.Error(w, "failed to send coin: "+err.Error(), http.StatusBadRequest)
@@ -208,40 +273,167 @@ func (h *Handler) SendCoin(w http.ResponseWriter, r *http.Request) {
 
 // BuyMerch обрабатывает GET /api/buy/{item}.
 // Выполняется покупка мерча за монеты.
+// Для мерча с вариантами (размер, цвет) нужен параметр ?sku=.
+// Офис выдачи можно выбрать параметром ?office=, промокод - параметром ?promo=.
+// Возвращает id заказа.
 func (h *Handler) BuyMerch(w http.ResponseWriter, r *http.Request) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "missing authorization header", http.StatusUnauthorized)
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
 		return
 	}
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+
+	vars := mux.Vars(r)
+	item := vars["item"]
+	if item == "" {
+		http.Error(w, "item parameter is required", http.StatusBadRequest)
 		return
 	}
-	token := parts[1]
-	userID, err := h.UserService.DecodeToken(token)
+
+	// Выполняем покупку мерча
+	query := r.URL.Query()
+	opts := service.BuyOptions{SKU: query.Get("sku"), Office: query.Get("office"), PromoCode: query.Get("promo")}
+	orderID, err := h.PurchasesService.BuyMerch(r.Context(), userID, item, opts)
 	if err != nil {
-		http.Error(w, "invalid token", http.StatusUnauthorized)
+		switch {
+		case errors.Is(err, repository.ErrOutOfStock):
+			http.Error(w, "out of stock", http.StatusConflict)
+		case errors.Is(err, repository.ErrPurchaseLimitExceeded):
+			http.Error(w, "purchase limit exceeded", http.StatusConflict)
+		case errors.Is(err, repository.ErrMerchUnavailable):
+			http.Error(w, "merch is not available now", http.StatusConflict)
+		case errors.Is(err, repository.ErrMerchNotFound), errors.Is(err, repository.ErrVariantNotFound),
+			errors.Is(err, repository.ErrPromoCodeNotFound):
+			http.Error(w, "failed to buy merch: "+err.Error(), http.StatusNotFound)
+		case errors.Is(err, repository.ErrPromoCodeExhausted):
+			http.Error(w, "failed to buy merch: "+err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "failed to buy merch: "+err.Error(), http.StatusBadRequest)
+		}
 		return
 	}
 
-	vars := mux.Vars(r)
-	item := vars["item"]
+	resp := struct {
+		Message string `json:"message"`
+		OrderID int    `json:"orderId"`
+	}{Message: "Purchase successful", OrderID: orderID}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ReturnMerch обрабатывает POST /api/return/{item}.
+// Возвращает одну единицу купленного мерча и зачисляет монеты тому, кто за нее платил.
+// Для мерча с вариантами нужен параметр ?sku=.
+func (h *Handler) ReturnMerch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	item := mux.Vars(r)["item"]
 	if item == "" {
 		http.Error(w, "item parameter is required", http.StatusBadRequest)
 		return
 	}
 
-	// Выполняем покупку мерча
-	if err := h.PurchasesService.BuyMerch(r.Context(), userID, item); err != nil {
-		http.Error(w, "failed to buy merch: "+err.Error(), http.StatusBadRequest)
+	refund, err := h.PurchasesService.ReturnMerch(r.Context(), userID, item, r.URL.Query().Get("sku"))
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, repository.ErrPurchaseNotFound), errors.Is(err, repository.ErrVariantNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, repository.ErrReturnWindowExpired):
+			status = http.StatusConflict
+		}
+		http.Error(w, "failed to return merch: "+err.Error(), status)
 		return
 	}
 
 	resp := struct {
+		Message  string `json:"message"`
+		Refunded int    `json:"refunded"`
+	}{Message: "Return successful", Refunded: refund}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GiftMerch обрабатывает POST /api/gift/{item}.
+// Покупает мерч за свои монеты и дарит его коллеге.
+// Тело запроса (JSON): toUser, message (необязательно), sku (для мерча с вариантами),
+// office (офис выдачи, необязательно), promoCode (необязательно).
+func (h *Handler) GiftMerch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	item := mux.Vars(r)["item"]
+	if item == "" {
+		http.Error(w, "item parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		ToUser  string `json:"toUser"`
 		Message string `json:"message"`
-	}{Message: "Purchase successful"}
+		SKU     string `json:"sku"`
+		Office    string `json:"office"`
+		PromoCode string `json:"promoCode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ToUser == "" {
+		http.Error(w, "toUser is required", http.StatusBadRequest)
+		return
+	}
+
+	giftID, err := h.PurchasesService.GiftMerch(r.Context(), userID, req.ToUser, item, req.Message, service.BuyOptions{SKU: req.SKU, Office: req.Office, PromoCode: req.PromoCode})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOutOfStock):
+			http.Error(w, "out of stock", http.StatusConflict)
+		case errors.Is(err, repository.ErrPurchaseLimitExceeded):
+			http.Error(w, "purchase limit exceeded", http.StatusConflict)
+		case errors.Is(err, repository.ErrMerchUnavailable):
+			http.Error(w, "merch is not available now", http.StatusConflict)
+		case errors.Is(err, repository.ErrMerchNotFound), errors.Is(err, repository.ErrVariantNotFound),
+			errors.Is(err, repository.ErrPromoCodeNotFound):
+			http.Error(w, "failed to gift merch: "+err.Error(), http.StatusNotFound)
+		case errors.Is(err, repository.ErrPromoCodeExhausted):
+			http.Error(w, "failed to gift merch: "+err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "failed to gift merch: "+err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	resp := struct {
+		Message string `json:"message"`
+		GiftID  int    `json:"giftId"`
+	}{Message: "Gift sent", GiftID: giftID}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
-}
\ No newline at end of file
+}
+
+// PurchaseHistory обрабатывает GET /api/purchases.
+// Возвращает каждую покупку отдельно: когда и по какой цене, сколько возвращено и статус заказа.
+// Агрегированный инвентарь остается в /api/info.
+func (h *Handler) PurchaseHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.PurchasesService.GetPurchaseHistory(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "failed to get purchase history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		history = []models.PurchaseHistoryItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
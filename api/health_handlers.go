@@ -0,0 +1,34 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"EmployeeMerchStore/internal/models"
+)
+
+// Healthz обрабатывает GET /healthz: процесс жив и отвечает на запросы.
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Status string `json:"status"`
+	}{Status: "ok"})
+}
+
+// Readyz обрабатывает GET /readyz: сервис может принимать трафик.
+// Если хоть одна проверка не прошла, отвечает 503 с подробностями.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ready, checks := h.HealthService.Readiness(r.Context())
+
+	resp := struct {
+		Status string               `json:"status"`
+		Checks []models.HealthCheck `json:"checks"`
+	}{Status: "ready", Checks: checks}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		resp.Status = "not ready"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,676 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/service"
+	"github.com/gorilla/mux"
+)
+
+type merchReq struct {
+	Name        string `json:"name"`
+	Price       int    `json:"price"`
+	Description string `json:"description"`
+	Stock       *int   `json:"stock"`
+}
+
+// Catalog обрабатывает GET /api/merch.
+// Возвращает каталог мерча с ценами и остатками (stock = null - без ограничений).
+// Фильтры: ?category= (slug категории) и ?tag=. Товары вне окна доступности не показываются.
+func (h *Handler) Catalog(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.userIDFromRequest(w, r); !ok {
+		return
+	}
+	h.writeMerchList(w, r, true)
+}
+
+// AdminListMerch обрабатывает GET /api/admin/merch.
+// ?archived=true возвращает архивные товары вместо активных.
+func (h *Handler) AdminListMerch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	h.writeMerchList(w, r, false)
+}
+
+func (h *Handler) writeMerchList(w http.ResponseWriter, r *http.Request, availableOnly bool) {
+	filter := models.MerchFilter{
+		Category:      r.URL.Query().Get("category"),
+		Tag:           r.URL.Query().Get("tag"),
+		AvailableOnly: availableOnly,
+		Archived:      !availableOnly && r.URL.Query().Get("archived") == "true",
+	}
+	merchList, err := h.MerchService.ListMerch(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "failed to list merch: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if merchList == nil {
+		merchList = []models.Merch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(merchList)
+}
+
+// AdminCreateMerch обрабатывает POST /api/admin/merch.
+// Тело запроса (JSON): name, price, description, stock (необязательно).
+func (h *Handler) AdminCreateMerch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	var req merchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.MerchService.CreateMerch(r.Context(), req.Name, req.Price, req.Description, req.Stock)
+	if err != nil {
+		http.Error(w, "failed to create merch: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusCreated)
+}
+
+// AdminUpdateMerch обрабатывает PUT /api/admin/merch/{id}.
+// Тело запроса (JSON): name, price, description. Остаток меняется отдельно.
+func (h *Handler) AdminUpdateMerch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req merchReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.MerchService.UpdateMerch(r.Context(), id, req.Name, req.Price, req.Description); err != nil {
+		http.Error(w, "failed to update merch: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminArchiveMerch обрабатывает DELETE /api/admin/merch/{id}.
+// Товар не удаляется, а уходит в архив: из каталога и продажи, но не из истории.
+func (h *Handler) AdminArchiveMerch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.MerchService.ArchiveMerch(r.Context(), id); err != nil {
+		http.Error(w, "failed to archive merch: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminRestoreMerch обрабатывает POST /api/admin/merch/{id}/restore.
+func (h *Handler) AdminRestoreMerch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.MerchService.RestoreMerch(r.Context(), id); err != nil {
+		http.Error(w, "failed to restore merch: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminRestockMerch обрабатывает POST /api/admin/merch/{id}/restock.
+// Тело запроса (JSON): quantity - сколько единиц добавить на склад.
+func (h *Handler) AdminRestockMerch(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Quantity int `json:"quantity"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.MerchService.Restock(r.Context(), id, req.Quantity); err != nil {
+		http.Error(w, "failed to restock merch: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminSetStock обрабатывает PUT /api/admin/merch/{id}/stock.
+// Тело запроса (JSON): stock - точный остаток, null снимает ограничение.
+func (h *Handler) AdminSetStock(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Stock *int `json:"stock"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.MerchService.SetStock(r.Context(), id, req.Stock); err != nil {
+		http.Error(w, "failed to set stock: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminSetPurchaseLimit обрабатывает PUT /api/admin/merch/{id}/limit.
+// Тело запроса (JSON): limit (null снимает ограничение), period (lifetime, month, quarter или year).
+func (h *Handler) AdminSetPurchaseLimit(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Limit  *int   `json:"limit"`
+		Period string `json:"period"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.MerchService.SetPurchaseLimit(r.Context(), id, req.Limit, req.Period); err != nil {
+		http.Error(w, "failed to set purchase limit: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+type variantReq struct {
+	SKU        string `json:"sku"`
+	Size       string `json:"size"`
+	Colour     string `json:"colour"`
+	PriceDelta int    `json:"priceDelta"`
+	Stock      *int   `json:"stock"`
+}
+
+// AdminListVariants обрабатывает GET /api/admin/merch/{id}/variants.
+func (h *Handler) AdminListVariants(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	variants, err := h.MerchService.GetVariants(r.Context(), id)
+	if err != nil {
+		http.Error(w, "failed to get variants: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if variants == nil {
+		variants = []models.MerchVariant{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(variants)
+}
+
+// AdminCreateVariant обрабатывает POST /api/admin/merch/{id}/variants.
+// Тело запроса (JSON): sku, size (S-XXL, необязательно), colour, priceDelta, stock.
+func (h *Handler) AdminCreateVariant(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	merchID, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req variantReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	_, err := h.MerchService.CreateVariant(r.Context(), models.MerchVariant{
+		MerchID:    merchID,
+		SKU:        req.SKU,
+		Size:       req.Size,
+		Colour:     req.Colour,
+		PriceDelta: req.PriceDelta,
+		Stock:      req.Stock,
+	})
+	if err != nil {
+		http.Error(w, "failed to create variant: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, merchID, http.StatusCreated)
+}
+
+// AdminUpdateVariant обрабатывает PUT /api/admin/merch/{id}/variants/{variantId}.
+// Тело запроса (JSON): sku, size, colour, priceDelta. Остаток меняется через restock.
+func (h *Handler) AdminUpdateVariant(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	merchID, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	variantID, err := strconv.Atoi(mux.Vars(r)["variantId"])
+	if err != nil {
+		http.Error(w, "invalid variant id", http.StatusBadRequest)
+		return
+	}
+
+	var req variantReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.MerchService.UpdateVariant(r.Context(), models.MerchVariant{
+		ID:         variantID,
+		MerchID:    merchID,
+		SKU:        req.SKU,
+		Size:       req.Size,
+		Colour:     req.Colour,
+		PriceDelta: req.PriceDelta,
+	})
+	if err != nil {
+		http.Error(w, "failed to update variant: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, merchID, http.StatusOK)
+}
+
+// AdminRestockVariant обрабатывает POST /api/admin/merch/{id}/variants/{variantId}/restock.
+// Тело запроса (JSON): quantity - сколько единиц варианта добавить на склад.
+func (h *Handler) AdminRestockVariant(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	merchID, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	variantID, err := strconv.Atoi(mux.Vars(r)["variantId"])
+	if err != nil {
+		http.Error(w, "invalid variant id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Quantity int `json:"quantity"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.MerchService.RestockVariant(r.Context(), merchID, variantID, req.Quantity); err != nil {
+		http.Error(w, "failed to restock variant: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, merchID, http.StatusOK)
+}
+
+func (h *Handler) writeMerch(w http.ResponseWriter, r *http.Request, id, status int) {
+	merch, err := h.MerchService.GetMerch(r.Context(), id)
+	if err != nil {
+		http.Error(w, "failed to get merch: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(merch)
+}
+
+// AdminSetCategory обрабатывает PUT /api/admin/merch/{id}/category.
+// Тело запроса (JSON): category (slug, пустая строка убирает категорию).
+func (h *Handler) AdminSetCategory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Category string `json:"category"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.MerchService.SetCategory(r.Context(), id, req.Category); err != nil {
+		http.Error(w, "failed to set category: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminSetTags обрабатывает PUT /api/admin/merch/{id}/tags.
+// Тело запроса (JSON): tags - новый список тегов, заменяет старый.
+func (h *Handler) AdminSetTags(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.MerchService.SetTags(r.Context(), id, req.Tags); err != nil {
+		http.Error(w, "failed to set tags: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminSetAvailability обрабатывает PUT /api/admin/merch/{id}/availability.
+// Тело запроса (JSON): from, until (RFC 3339, null - без ограничения).
+func (h *Handler) AdminSetAvailability(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		From  *time.Time `json:"from"`
+		Until *time.Time `json:"until"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.MerchService.SetAvailability(r.Context(), id, req.From, req.Until); err != nil {
+		http.Error(w, "failed to set availability: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	h.writeMerch(w, r, id, http.StatusOK)
+}
+
+// AdminScheduledPrices обрабатывает GET /api/admin/merch/{id}/prices.
+func (h *Handler) AdminScheduledPrices(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	changes, err := h.MerchService.GetScheduledPrices(r.Context(), id)
+	if err != nil {
+		http.Error(w, "failed to get scheduled prices: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if changes == nil {
+		changes = []models.ScheduledPrice{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// AdminSchedulePrice обрабатывает POST /api/admin/merch/{id}/prices.
+// Тело запроса (JSON): price, effectiveAt (RFC 3339, в будущем).
+func (h *Handler) AdminSchedulePrice(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Price       int       `json:"price"`
+		EffectiveAt time.Time `json:"effectiveAt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	changeID, err := h.MerchService.SchedulePrice(r.Context(), id, req.Price, req.EffectiveAt)
+	if err != nil {
+		http.Error(w, "failed to schedule price: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		ID int `json:"id"`
+	}{ID: changeID})
+}
+
+// AdminCancelScheduledPrice обрабатывает DELETE /api/admin/merch/{id}/prices/{changeId}.
+// Отменить можно только еще не примененную смену цены.
+func (h *Handler) AdminCancelScheduledPrice(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	changeID, err := strconv.Atoi(mux.Vars(r)["changeId"])
+	if err != nil {
+		http.Error(w, "invalid price change id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.MerchService.CancelScheduledPrice(r.Context(), id, changeID); err != nil {
+		http.Error(w, "failed to cancel scheduled price: "+err.Error(), merchErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Categories обрабатывает GET /api/categories.
+func (h *Handler) Categories(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.userIDFromRequest(w, r); !ok {
+		return
+	}
+
+	categories, err := h.MerchService.ListCategories(r.Context())
+	if err != nil {
+		http.Error(w, "failed to list categories: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if categories == nil {
+		categories = []models.Category{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// AdminCreateCategory обрабатывает POST /api/admin/categories.
+// Тело запроса (JSON): slug, name.
+func (h *Handler) AdminCreateCategory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	var req struct {
+		Slug string `json:"slug"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.MerchService.CreateCategory(r.Context(), req.Slug, req.Name)
+	if err != nil {
+		http.Error(w, "failed to create category: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		ID int `json:"id"`
+	}{ID: id})
+}
+
+// AdminUploadImage обрабатывает POST /api/admin/merch/{id}/images.
+// Картинка передается в multipart/form-data в поле image (jpeg, png или gif).
+func (h *Handler) AdminUploadImage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Запас на заголовки multipart поверх размера самой картинки
+	r.Body = http.MaxBytesReader(w, r.Body, h.ImageService.MaxSize()+1<<20)
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, "image file is required: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	img, err := h.ImageService.Upload(r.Context(), id, file)
+	if err != nil {
+		http.Error(w, "failed to upload image: "+err.Error(), imageErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(img)
+}
+
+// AdminDeleteImage обрабатывает DELETE /api/admin/merch/{id}/images/{imageId}.
+func (h *Handler) AdminDeleteImage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, ok := merchIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	imageID, err := strconv.Atoi(mux.Vars(r)["imageId"])
+	if err != nil {
+		http.Error(w, "invalid image id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ImageService.Delete(r.Context(), id, imageID); err != nil {
+		http.Error(w, "failed to delete image: "+err.Error(), imageErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func merchIDFromRequest(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid merch id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func merchErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrMerchNameTaken) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, repository.ErrMerchNotFound) || errors.Is(err, repository.ErrVariantNotFound) ||
+		errors.Is(err, repository.ErrCategoryNotFound) || errors.Is(err, repository.ErrScheduledPriceNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func imageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrMerchNotFound), errors.Is(err, repository.ErrImageNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, service.ErrUnsupportedImage):
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// Orders обрабатывает GET /api/orders.
+// По умолчанию возвращает только открытые заказы сотрудника, с ?all=true - все.
+func (h *Handler) Orders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	orders, err := h.OrderService.GetUserOrders(r.Context(), userID, !all)
+	if err != nil {
+		http.Error(w, "failed to get orders: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if orders == nil {
+		orders = []models.Order{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// Offices обрабатывает GET /api/offices.
+// Возвращает офисы, в которых можно забрать заказ.
+func (h *Handler) Offices(w http.ResponseWriter, r *http.Request) {
+	offices := h.OrderService.Offices()
+	if offices == nil {
+		offices = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(offices)
+}
+
+// AdminListOrders обрабатывает GET /api/admin/orders.
+// Фильтр по статусу - параметр ?status=.
+func (h *Handler) AdminListOrders(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	orders, err := h.OrderService.ListOrders(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, "failed to list orders: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if orders == nil {
+		orders = []models.Order{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// AdminUpdateOrderStatus обрабатывает PUT /api/admin/orders/{id}/status.
+// Тело запроса (JSON): status. Отмена заказа возвращает монеты плательщику.
+func (h *Handler) AdminUpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid order id", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.OrderService.UpdateOrderStatus(r.Context(), id, req.Status)
+	if err != nil {
+		http.Error(w, "failed to update order: "+err.Error(), orderErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrInvalidOrderTransition), errors.Is(err, repository.ErrPurchaseNotFound):
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
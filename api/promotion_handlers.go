@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// Promotions обрабатывает GET /api/promotions.
+// Возвращает действующие сейчас акции.
+func (h *Handler) Promotions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.userIDFromRequest(w, r); !ok {
+		return
+	}
+	h.writePromotions(w, r, true)
+}
+
+// AdminListPromotions обрабатывает GET /api/admin/promotions.
+// Возвращает все акции, включая завершенные и будущие.
+func (h *Handler) AdminListPromotions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+	h.writePromotions(w, r, false)
+}
+
+func (h *Handler) writePromotions(w http.ResponseWriter, r *http.Request, activeOnly bool) {
+	promotions, err := h.PromotionService.ListPromotions(r.Context(), activeOnly)
+	if err != nil {
+		http.Error(w, "failed to list promotions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if promotions == nil {
+		promotions = []models.Promotion{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+// AdminCreatePromotion обрабатывает POST /api/admin/promotions.
+// Тело запроса (JSON): name, merch_id или category (без них - на весь магазин), discount_type (percent|fixed),
+// discount_value, starts_at, ends_at (RFC 3339).
+func (h *Handler) AdminCreatePromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	var req models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.PromotionService.CreatePromotion(r.Context(), req)
+	if err != nil {
+		http.Error(w, "failed to create promotion: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		ID int `json:"id"`
+	}{ID: id})
+}
+
+// AdminEndPromotion обрабатывает POST /api/admin/promotions/{id}/end.
+// Досрочно завершает акцию.
+func (h *Handler) AdminEndPromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid promotion id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.PromotionService.EndPromotion(r.Context(), id); err != nil {
+		http.Error(w, "failed to end promotion: "+err.Error(), promotionErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminListPromoCodes обрабатывает GET /api/admin/promo-codes.
+func (h *Handler) AdminListPromoCodes(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	codes, err := h.PromotionService.ListPromoCodes(r.Context())
+	if err != nil {
+		http.Error(w, "failed to list promo codes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if codes == nil {
+		codes = []models.PromoCode{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(codes)
+}
+
+// AdminCreatePromoCode обрабатывает POST /api/admin/promo-codes.
+// Тело запроса (JSON): code, merch_id, discount_type, discount_value,
+// max_uses (1 - одноразовый, без него - без ограничений), starts_at, ends_at.
+func (h *Handler) AdminCreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	var req models.PromoCode
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.PromotionService.CreatePromoCode(r.Context(), req)
+	if err != nil {
+		http.Error(w, "failed to create promo code: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		ID int `json:"id"`
+	}{ID: id})
+}
+
+// AdminDisablePromoCode обрабатывает POST /api/admin/promo-codes/{id}/disable.
+func (h *Handler) AdminDisablePromoCode(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.adminIDFromRequest(w, r); !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid promo code id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.PromotionService.DisablePromoCode(r.Context(), id); err != nil {
+		http.Error(w, "failed to disable promo code: "+err.Error(), promotionErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func promotionErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrPromotionNotFound) || errors.Is(err, repository.ErrPromoCodeNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
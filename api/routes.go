@@ -3,11 +3,22 @@ package api
 import (
 	"net/http"
 
+	"EmployeeMerchStore/internal/metrics"
 	"github.com/gorilla/mux"
 )
 
+// mediaServer - хранилище, которое умеет само раздавать файлы по HTTP.
+type mediaServer interface {
+	http.Handler
+	BaseURL() string
+}
+
 func RegisterRoutes(h *Handler) http.Handler {
 	router := mux.NewRouter()
+	router.Use(routeSpanName, metrics.Middleware)
+
+	router.HandleFunc("/healthz", h.Healthz).Methods("GET")
+	router.HandleFunc("/readyz", h.Readyz).Methods("GET")
 
 	router.HandleFunc("/api/auth", h.Auth).Methods("POST")
 
@@ -19,5 +30,73 @@ func RegisterRoutes(h *Handler) http.Handler {
 
 	router.HandleFunc("/api/buy/{item}", h.BuyMerch).Methods("GET")
 
-	return router
+	router.HandleFunc("/api/return/{item}", h.ReturnMerch).Methods("POST")
+
+	router.HandleFunc("/api/purchases", h.PurchaseHistory).Methods("GET")
+
+	router.HandleFunc("/api/gift/{item}", h.GiftMerch).Methods("POST")
+
+	router.HandleFunc("/api/orders", h.Orders).Methods("GET")
+	router.HandleFunc("/api/offices", h.Offices).Methods("GET")
+
+	router.HandleFunc("/api/transfers/pending", h.PendingTransfers).Methods("GET")
+	router.HandleFunc("/api/transfers/{id:[0-9]+}/accept", h.AcceptTransfer).Methods("POST")
+	router.HandleFunc("/api/transfers/{id:[0-9]+}/decline", h.DeclineTransfer).Methods("POST")
+	router.HandleFunc("/api/transfers/{id:[0-9]+}/cancel", h.CancelTransfer).Methods("POST")
+
+	router.HandleFunc("/api/merch", h.Catalog).Methods("GET")
+	router.HandleFunc("/api/categories", h.Categories).Methods("GET")
+	router.HandleFunc("/api/promotions", h.Promotions).Methods("GET")
+
+	router.HandleFunc("/api/wishlist", h.Wishlist).Methods("GET")
+	router.HandleFunc("/api/wishlist/{item}", h.AddToWishlist).Methods("POST")
+	router.HandleFunc("/api/wishlist/{item}", h.RemoveFromWishlist).Methods("DELETE")
+
+	router.HandleFunc("/api/notifications", h.Notifications).Methods("GET")
+	router.HandleFunc("/api/notifications/{id:[0-9]+}/read", h.ReadNotification).Methods("POST")
+
+	router.HandleFunc("/api/admin/transfers/{id:[0-9]+}/reverse", h.ReverseTransfer).Methods("POST")
+
+	router.HandleFunc("/api/admin/merch", h.AdminListMerch).Methods("GET")
+	router.HandleFunc("/api/admin/merch", h.AdminCreateMerch).Methods("POST")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}", h.AdminUpdateMerch).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}", h.AdminArchiveMerch).Methods("DELETE")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/restore", h.AdminRestoreMerch).Methods("POST")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/restock", h.AdminRestockMerch).Methods("POST")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/stock", h.AdminSetStock).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/limit", h.AdminSetPurchaseLimit).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/category", h.AdminSetCategory).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/tags", h.AdminSetTags).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/availability", h.AdminSetAvailability).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/prices", h.AdminScheduledPrices).Methods("GET")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/prices", h.AdminSchedulePrice).Methods("POST")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/prices/{changeId:[0-9]+}", h.AdminCancelScheduledPrice).Methods("DELETE")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/images", h.AdminUploadImage).Methods("POST")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/images/{imageId:[0-9]+}", h.AdminDeleteImage).Methods("DELETE")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/variants", h.AdminListVariants).Methods("GET")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/variants", h.AdminCreateVariant).Methods("POST")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/variants/{variantId:[0-9]+}", h.AdminUpdateVariant).Methods("PUT")
+	router.HandleFunc("/api/admin/merch/{id:[0-9]+}/variants/{variantId:[0-9]+}/restock", h.AdminRestockVariant).Methods("POST")
+
+	router.HandleFunc("/api/admin/orders", h.AdminListOrders).Methods("GET")
+	router.HandleFunc("/api/admin/orders/{id:[0-9]+}/status", h.AdminUpdateOrderStatus).Methods("PUT")
+
+	router.HandleFunc("/api/admin/categories", h.AdminCreateCategory).Methods("POST")
+
+	router.HandleFunc("/api/admin/promotions", h.AdminListPromotions).Methods("GET")
+	router.HandleFunc("/api/admin/promotions", h.AdminCreatePromotion).Methods("POST")
+	router.HandleFunc("/api/admin/promotions/{id:[0-9]+}/end", h.AdminEndPromotion).Methods("POST")
+	router.HandleFunc("/api/admin/promo-codes", h.AdminListPromoCodes).Methods("GET")
+	router.HandleFunc("/api/admin/promo-codes", h.AdminCreatePromoCode).Methods("POST")
+	router.HandleFunc("/api/admin/promo-codes/{id:[0-9]+}/disable", h.AdminDisablePromoCode).Methods("POST")
+
+	// Локальное хранилище само раздает загруженные картинки
+	if media, ok := h.ImageService.Storage.(mediaServer); ok {
+		prefix := media.BaseURL() + "/"
+		router.PathPrefix(prefix).Methods("GET").Handler(http.StripPrefix(prefix, media))
+	}
+
+	// Оборачиваем весь роутер, чтобы ID, спан и строка в логе были и у 404/405.
+	// Спан снаружи, чтобы trace_id попал и в строку лога о запросе.
+	return h.withTracing(h.withRequestLogging(router))
 }
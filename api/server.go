@@ -0,0 +1,57 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+
+	"EmployeeMerchStore/config"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewServer собирает http.Server с адресом, таймаутами и TLS из конфига.
+func NewServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	srv := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       time.Duration(cfg.ReadTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout) * time.Second,
+		WriteTimeout:      time.Duration(cfg.WriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeout) * time.Second,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes << 10,
+	}
+
+	if cfg.TLS.CertFile != "" {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		if !cfg.HTTP2 {
+			// Непустой TLSNextProto отключает автоматический HTTP/2
+			srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		}
+	}
+	return srv
+}
+
+// ListenAndServe запускает srv по HTTPS, если в конфиге задан сертификат, иначе по HTTP.
+func ListenAndServe(srv *http.Server, cfg config.ServerConfig) error {
+	if cfg.TLS.CertFile != "" {
+		return srv.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	}
+	return srv.ListenAndServe()
+}
+
+// NewMetricsServer собирает отдельный сервер, который отдает только GET /metrics.
+// Возвращает nil, если метрики выключены в конфиге.
+func NewMetricsServer(cfg config.MetricsConfig) *http.Server {
+	if cfg.Port == 0 {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
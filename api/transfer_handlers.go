@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// PendingTransfers обрабатывает GET /api/transfers/pending.
+// Возвращает ожидающие переводы: входящие (их можно принять или отклонить)
+// и исходящие (их можно отменить).
+func (h *Handler) PendingTransfers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	transfers, err := h.LedgerService.GetPendingTransfers(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "failed to get pending transfers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := struct {
+		Incoming []models.PendingTransfer `json:"incoming"`
+		Outgoing []models.PendingTransfer `json:"outgoing"`
+	}{
+		Incoming: []models.PendingTransfer{},
+		Outgoing: []models.PendingTransfer{},
+	}
+	for _, pt := range transfers {
+		if pt.ToUserID == userID {
+			resp.Incoming = append(resp.Incoming, pt)
+		} else {
+			resp.Outgoing = append(resp.Outgoing, pt)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AcceptTransfer обрабатывает POST /api/transfers/{id}/accept.
+func (h *Handler) AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveTransfer(w, r, h.LedgerService.AcceptTransfer, "Coin transfer accepted")
+}
+
+// DeclineTransfer обрабатывает POST /api/transfers/{id}/decline.
+func (h *Handler) DeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveTransfer(w, r, h.LedgerService.DeclineTransfer, "Coin transfer declined")
+}
+
+// CancelTransfer обрабатывает POST /api/transfers/{id}/cancel.
+func (h *Handler) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveTransfer(w, r, h.LedgerService.CancelTransfer, "Coin transfer cancelled")
+}
+
+type resolveFunc func(ctx context.Context, userID string, transferID int) error
+
+func (h *Handler) resolveTransfer(w http.ResponseWriter, r *http.Request, resolve resolveFunc, message string) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	transferID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid transfer id", http.StatusBadRequest)
+		return
+	}
+
+	if err := resolve(r.Context(), userID, transferID); err != nil {
+		http.Error(w, err.Error(), transferErrorStatus(err))
+		return
+	}
+
+	resp := struct {
+		Message string `json:"message"`
+	}{Message: message}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// transferErrorStatus подбирает HTTP-статус для ошибки перевода.
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrPendingTransferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrPendingTransferClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
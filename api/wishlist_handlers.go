@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"github.com/gorilla/mux"
+)
+
+// Wishlist обрабатывает GET /api/wishlist.
+// Возвращает баланс и товары из вишлиста с прогрессом накопления на каждый.
+func (h *Handler) Wishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	balance, items, err := h.WishlistService.GetWishlist(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "failed to get wishlist: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if items == nil {
+		items = []models.WishlistItem{}
+	}
+
+	resp := struct {
+		Coins int                   `json:"coins"`
+		Items []models.WishlistItem `json:"items"`
+	}{Coins: balance, Items: items}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AddToWishlist обрабатывает POST /api/wishlist/{item}.
+func (h *Handler) AddToWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.WishlistService.AddToWishlist(r.Context(), userID, mux.Vars(r)["item"]); err != nil {
+		http.Error(w, "failed to add to wishlist: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := struct {
+		Message string `json:"message"`
+	}{Message: "Added to wishlist"}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RemoveFromWishlist обрабатывает DELETE /api/wishlist/{item}.
+func (h *Handler) RemoveFromWishlist(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.WishlistService.RemoveFromWishlist(r.Context(), userID, mux.Vars(r)["item"]); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, repository.ErrWishlistItemNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, "failed to remove from wishlist: "+err.Error(), status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Notifications обрабатывает GET /api/notifications.
+// С параметром ?unread=true возвращает только непрочитанные.
+func (h *Handler) Notifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+	notifications, err := h.WishlistService.GetNotifications(r.Context(), userID, unreadOnly)
+	if err != nil {
+		http.Error(w, "failed to get notifications: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// ReadNotification обрабатывает POST /api/notifications/{id}/read.
+func (h *Handler) ReadNotification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "invalid notification id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.WishlistService.MarkNotificationRead(r.Context(), userID, id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repository.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,162 @@
+// merchctl - консольная утилита для эксплуатации магазина: миграции, пользователи,
+// начисление монет, каталог, сверка балансов и отчеты без ручного SQL.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"EmployeeMerchStore/config"
+	"EmployeeMerchStore/internal/database"
+	"EmployeeMerchStore/internal/logging"
+	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/service"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const usage = `Usage: merchctl [-config path] <command> [arguments]
+
+Commands:
+  migrate up                         apply pending migrations
+  migrate down [-steps N]            roll back the last N migrations (default 1)
+  migrate status                     list migrations and when they were applied
+  seed                               load the starter catalog
+
+  user create -username U -password P [-admin]
+  user admin -username U [-revoke]   grant or revoke admin rights
+  coins grant -username U -amount N  credit coins to a user
+
+  merch list [-archived]
+  merch create -name N -price P [-description D] [-stock S]
+  merch archive -id ID
+  merch restore -id ID
+  merch restock -id ID -quantity Q
+
+  reconcile                          find users whose balance does not match the ledger
+  report balances [-format csv|json]
+  report purchases [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-format csv|json]
+`
+
+// errUsage - неверные аргументы, печатаем справку и выходим с кодом 2.
+var errUsage = errors.New("invalid arguments")
+
+// app собирает зависимости для команд так же, как cmd/server.
+type app struct {
+	cfg    *config.Config
+	db     *pgxpool.Pool
+	logger *slog.Logger
+
+	users   *service.UserService
+	ledger  *service.LedgerService
+	merch   *service.MerchService
+	reports *service.ReportService
+}
+
+func main() {
+	flags := flag.NewFlagSet("merchctl", flag.ExitOnError)
+	configPath := flags.String("config", "config/config.yml", "path to the config file")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fail(fmt.Errorf("error loading config: %w", err))
+	}
+
+	// Логи идут в stderr, чтобы не смешиваться с отчетами в stdout
+	logger, err := logging.New(cfg.Log, os.Stderr)
+	if err != nil {
+		fail(err)
+	}
+
+	db, err := database.InitDB(cfg, logger)
+	if err != nil {
+		fail(fmt.Errorf("database connection failed: %w", err))
+	}
+
+	// os.Exit не выполняет defer, поэтому пул закрываем до выхода
+	a := newApp(cfg, db, logger)
+	err = a.run(context.Background(), flags.Args())
+	a.users.Close()
+	db.Close()
+
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(os.Stderr, "merchctl: %v\n\n%s", err, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func newApp(cfg *config.Config, db *pgxpool.Pool, logger *slog.Logger) *app {
+	userRepo := repository.NewUserRepository(db)
+	merchRepo := repository.NewMerchRepository(db)
+	wishlistRepo := repository.NewWishlistRepository(db)
+
+	return &app{
+		cfg:     cfg,
+		db:      db,
+		logger:  logger,
+		users:   service.NewUserService(userRepo, cfg),
+		ledger:  service.NewLedgerService(repository.NewLedgerRepository(db), userRepo, cfg, logger),
+		merch:   service.NewMerchService(merchRepo, wishlistRepo, logger),
+		reports: service.NewReportService(repository.NewReportRepository(db)),
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	command, rest := args[0], args[1:]
+	switch command {
+	case "migrate":
+		return a.migrate(ctx, rest)
+	case "seed":
+		return database.Seed(ctx, a.db, a.logger)
+	case "user":
+		return a.user(ctx, rest)
+	case "coins":
+		return a.coins(ctx, rest)
+	case "merch":
+		return a.merchCommand(ctx, rest)
+	case "reconcile":
+		return a.reconcile(ctx)
+	case "report":
+		return a.report(ctx, rest)
+	}
+	return fmt.Errorf("%w: unknown command %q", errUsage, command)
+}
+
+// subcommand отделяет имя подкоманды от ее флагов.
+func subcommand(command string, args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%w: %s needs a subcommand", errUsage, command)
+	}
+	return args[0], args[1:], nil
+}
+
+// parseFlags разбирает флаги подкоманды; ошибки разбора считаются ошибками использования.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v", errUsage, fs.Args())
+	}
+	return nil
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "merchctl: %v\n", err)
+	os.Exit(1)
+}
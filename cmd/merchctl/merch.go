@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"EmployeeMerchStore/internal/models"
+)
+
+func (a *app) merchCommand(ctx context.Context, args []string) error {
+	sub, rest, err := subcommand("merch", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		fs := flag.NewFlagSet("merch list", flag.ContinueOnError)
+		archived := fs.Bool("archived", false, "list archived items instead of active ones")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+
+		merchList, err := a.merch.ListMerch(ctx, models.MerchFilter{Archived: *archived})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPRICE\tSTOCK\tCATEGORY")
+		for _, m := range merchList {
+			stock := "unlimited"
+			if m.Stock != nil {
+				stock = fmt.Sprint(*m.Stock)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", m.ID, m.Name, m.Price, stock, m.Category)
+		}
+		return w.Flush()
+
+	case "create":
+		fs := flag.NewFlagSet("merch create", flag.ContinueOnError)
+		name := fs.String("name", "", "item name")
+		price := fs.Int("price", 0, "price in coins")
+		description := fs.String("description", "", "item description")
+		stock := fs.Int("stock", -1, "units in stock, -1 for unlimited")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+
+		var stockPtr *int
+		if *stock >= 0 {
+			stockPtr = stock
+		}
+		id, err := a.merch.CreateMerch(ctx, *name, *price, *description, stockPtr)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created merch %s with id %d\n", *name, id)
+		return nil
+
+	case "archive", "restore":
+		fs := flag.NewFlagSet("merch "+sub, flag.ContinueOnError)
+		id := fs.Int("id", 0, "item id")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+		if *id <= 0 {
+			return fmt.Errorf("%w: -id is required", errUsage)
+		}
+
+		if sub == "archive" {
+			if err := a.merch.ArchiveMerch(ctx, *id); err != nil {
+				return err
+			}
+			fmt.Printf("Archived merch %d\n", *id)
+			return nil
+		}
+		if err := a.merch.RestoreMerch(ctx, *id); err != nil {
+			return err
+		}
+		fmt.Printf("Restored merch %d\n", *id)
+		return nil
+
+	case "restock":
+		fs := flag.NewFlagSet("merch restock", flag.ContinueOnError)
+		id := fs.Int("id", 0, "item id")
+		quantity := fs.Int("quantity", 0, "units to add")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+		if *id <= 0 {
+			return fmt.Errorf("%w: -id is required", errUsage)
+		}
+
+		stock, err := a.merch.Restock(ctx, *id, *quantity)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restocked merch %d, %d in stock\n", *id, stock)
+		return nil
+	}
+	return fmt.Errorf("%w: unknown merch subcommand %q", errUsage, sub)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"EmployeeMerchStore/internal/database"
+)
+
+func (a *app) migrate(ctx context.Context, args []string) error {
+	sub, rest, err := subcommand("migrate", args)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := database.NewMigrator(a.db, database.Migrations(), a.logger)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "up":
+		if err := parseFlags(flag.NewFlagSet("migrate up", flag.ContinueOnError), rest); err != nil {
+			return err
+		}
+		return migrator.Up(ctx)
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "how many migrations to roll back")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+		if *steps <= 0 {
+			return fmt.Errorf("%w: -steps must be positive", errUsage)
+		}
+		return migrator.Down(ctx, *steps)
+
+	case "status":
+		if err := parseFlags(flag.NewFlagSet("migrate status", flag.ContinueOnError), rest); err != nil {
+			return err
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("%w: unknown migrate subcommand %q", errUsage, sub)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// errMismatch - сверка нашла расхождения; код выхода 1 позволяет звать reconcile из cron.
+var errMismatch = errors.New("balances do not match the ledger")
+
+func (a *app) reconcile(ctx context.Context) error {
+	mismatches, err := a.reports.ReconcileBalances(ctx)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) == 0 {
+		fmt.Println("All balances match the ledger")
+		return nil
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"user_id", "username", "balance", "expected", "difference"})
+	for _, m := range mismatches {
+		w.Write([]string{m.UserID, m.Username, strconv.Itoa(m.Balance), strconv.Itoa(m.Expected),
+			strconv.Itoa(m.Balance - m.Expected)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %d users", errMismatch, len(mismatches))
+}
+
+func (a *app) report(ctx context.Context, args []string) error {
+	sub, rest, err := subcommand("report", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "balances":
+		fs := flag.NewFlagSet("report balances", flag.ContinueOnError)
+		format := fs.String("format", "csv", "csv or json")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+
+		balances, err := a.reports.GetBalances(ctx)
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(balances))
+		for _, b := range balances {
+			rows = append(rows, []string{b.UserID, b.Username, strconv.Itoa(b.Balance),
+				strconv.FormatBool(b.IsAdmin), b.CreatedAt.Format(time.RFC3339)})
+		}
+		return writeReport(*format, balances, []string{"user_id", "username", "balance", "is_admin", "created_at"}, rows)
+
+	case "purchases":
+		fs := flag.NewFlagSet("report purchases", flag.ContinueOnError)
+		now := time.Now()
+		fromFlag := fs.String("from", now.AddDate(0, -1, 0).Format(dateLayout), "first day of the period")
+		toFlag := fs.String("to", now.Format(dateLayout), "last day of the period, inclusive")
+		format := fs.String("format", "csv", "csv or json")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+
+		from, err := time.ParseInLocation(dateLayout, *fromFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("%w: -from: %v", errUsage, err)
+		}
+		to, err := time.ParseInLocation(dateLayout, *toFlag, time.Local)
+		if err != nil {
+			return fmt.Errorf("%w: -to: %v", errUsage, err)
+		}
+
+		// -to включительно: берем покупки до начала следующего дня
+		purchases, err := a.reports.GetPurchases(ctx, from, to.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, 0, len(purchases))
+		for _, p := range purchases {
+			rows = append(rows, []string{strconv.Itoa(p.PurchaseID), p.PurchasedAt.Format(time.RFC3339), p.Username,
+				p.Merch, p.SKU, strconv.Itoa(p.Quantity), strconv.Itoa(p.Returned), strconv.Itoa(p.UnitPrice),
+				strconv.Itoa(p.Discount), p.Office, p.OrderStatus})
+		}
+		header := []string{"purchase_id", "purchased_at", "username", "merch", "sku", "quantity", "returned",
+			"unit_price", "discount", "office", "order_status"}
+		return writeReport(*format, purchases, header, rows)
+	}
+	return fmt.Errorf("%w: unknown report subcommand %q", errUsage, sub)
+}
+
+// writeReport печатает отчет в stdout: CSV из header и rows или JSON из data.
+func writeReport(format string, data interface{}, header []string, rows [][]string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(header)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+	return fmt.Errorf("%w: unknown format %q", errUsage, format)
+}
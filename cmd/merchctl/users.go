@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+func (a *app) user(ctx context.Context, args []string) error {
+	sub, rest, err := subcommand("user", args)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+		username := fs.String("username", "", "username")
+		password := fs.String("password", "", "password")
+		admin := fs.Bool("admin", false, "grant admin rights")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+		if *username == "" || *password == "" {
+			return fmt.Errorf("%w: -username and -password are required", errUsage)
+		}
+
+		if _, err := a.users.CreateUser(ctx, *username, *password); err != nil {
+			return err
+		}
+		if *admin {
+			if err := a.users.SetAdmin(ctx, *username, true); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Created user %s\n", *username)
+		return nil
+
+	case "admin":
+		fs := flag.NewFlagSet("user admin", flag.ContinueOnError)
+		username := fs.String("username", "", "username")
+		revoke := fs.Bool("revoke", false, "revoke admin rights instead of granting them")
+		if err := parseFlags(fs, rest); err != nil {
+			return err
+		}
+		if *username == "" {
+			return fmt.Errorf("%w: -username is required", errUsage)
+		}
+
+		if err := a.users.SetAdmin(ctx, *username, !*revoke); err != nil {
+			return err
+		}
+		if *revoke {
+			fmt.Printf("Revoked admin rights from %s\n", *username)
+		} else {
+			fmt.Printf("Granted admin rights to %s\n", *username)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown user subcommand %q", errUsage, sub)
+}
+
+func (a *app) coins(ctx context.Context, args []string) error {
+	sub, rest, err := subcommand("coins", args)
+	if err != nil {
+		return err
+	}
+	if sub != "grant" {
+		return fmt.Errorf("%w: unknown coins subcommand %q", errUsage, sub)
+	}
+
+	fs := flag.NewFlagSet("coins grant", flag.ContinueOnError)
+	username := fs.String("username", "", "username")
+	amount := fs.Int("amount", 0, "how many coins to credit")
+	if err := parseFlags(fs, rest); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("%w: -username is required", errUsage)
+	}
+
+	if err := a.ledger.GrantCoins(ctx, *username, *amount); err != nil {
+		return err
+	}
+	fmt.Printf("Granted %d coins to %s\n", *amount, *username)
+	return nil
+}
@@ -1,54 +1,194 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"net/http"
-	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
 	"EmployeeMerchStore/api"
 	"EmployeeMerchStore/config"
 	"EmployeeMerchStore/internal/database"
+	"EmployeeMerchStore/internal/logging"
+	"EmployeeMerchStore/internal/metrics"
 	"EmployeeMerchStore/internal/repository"
 	"EmployeeMerchStore/internal/service"
+	"EmployeeMerchStore/internal/storage"
+	"EmployeeMerchStore/internal/tracing"
 )
 
 func main() {
+	configPath := flag.String("config", "config/config.yml", "path to the config file, env vars override its values")
+	flag.Parse()
+
 	// Загружаем конфиг
-	cfg, err := config.LoadConfig("config/config.yml")
+	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
+	// Логгер из конфига; он же становится логгером по умолчанию для log и slog
+	logger, err := logging.New(cfg.Log, os.Stderr)
+	if err != nil {
+		log.Fatalf("Logger init failed: %v", err)
+	}
+	slog.SetDefault(logger)
+	fatal := func(msg string, err error) {
+		logger.Error(msg, "error", err)
+		os.Exit(1)
+	}
+
+	// Трассировку настраиваем до пула, чтобы запросы pgx тоже попадали в трейсы
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, os.Stdout)
+	if err != nil {
+		fatal("Tracing init failed", err)
+	}
+
 	// Подключаемся к БД
-	dbPool, err := database.InitDB(cfg)
+	dbPool, err := database.InitDB(cfg, logger)
 	if err != nil {
-		log.Fatalf("Database connection failed: %v", err)
+		fatal("Database connection failed", err)
 	}
-	defer dbPool.Close()
 
-	// 3. Запускаем миграции
-	ctx := context.Background()
-	err = database.RunMigrations(ctx, dbPool)
+	// SIGINT/SIGTERM отменяют ctx и запускают плавную остановку
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 3. Запускаем миграции; мигратор нужен и readiness-пробе
+	migrator, err := database.NewMigrator(dbPool, database.Migrations(), logger)
+	if err != nil {
+		fatal("Migrator init failed", err)
+	}
+	err = migrator.Up(ctx)
 	if err != nil {
-		log.Fatalf("Migration failed: %v", err)
+		fatal("Migration failed", err)
+	}
+
+	// Стартовый каталог, если включен в конфиге
+	if cfg.Database.Seed {
+		if err := database.Seed(ctx, dbPool, logger); err != nil {
+			fatal("Seeding failed", err)
+		}
 	}
 
 	// Создаем репозитории
 	userRepo := repository.NewUserRepository(dbPool)
 	purchasesRepo := repository.NewPurchasesRepository(dbPool)
 	ledgerRepo := repository.NewLedgerRepository(dbPool)
-	// merchrRepo := repository.NewMerchRepository(dbPool)
+	merchRepo := repository.NewMerchRepository(dbPool)
+	wishlistRepo := repository.NewWishlistRepository(dbPool)
+	orderRepo := repository.NewOrderRepository(dbPool)
+	promotionRepo := repository.NewPromotionRepository(dbPool)
+
+	// Хранилище картинок мерча
+	mediaStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		fatal("Storage init failed", err)
+	}
 
 	// Создаем сервисы
 	userService := service.NewUserService(userRepo, cfg)
-	purchasesService := service.NewPurchasesService(purchasesRepo, userRepo)
-	ledgerService := service.NewLedgerService(ledgerRepo, userRepo)
+	purchasesService := service.NewPurchasesService(purchasesRepo, userRepo, cfg)
+	ledgerService := service.NewLedgerService(ledgerRepo, userRepo, cfg, logger)
+	merchService := service.NewMerchService(merchRepo, wishlistRepo, logger)
+	wishlistService := service.NewWishlistService(wishlistRepo, purchasesRepo, userRepo)
+	orderService := service.NewOrderService(orderRepo, cfg)
+	promotionService := service.NewPromotionService(promotionRepo)
+	imageService := service.NewImageService(merchRepo, mediaStorage, cfg, logger)
+	healthService := service.NewHealthService(dbPool, migrator)
+
+	// Метрики пула и кэша снимаются при каждом скрейпе /metrics (порт metrics.port)
+	metrics.RegisterPool(dbPool)
+	metrics.RegisterCache("auth_tokens", userService)
+
+	// Фоновые задачи останавливаем только после того, как сервер дождался текущих запросов
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(2)
+
+	// Возвращаем отправителям непринятые отложенные переводы
+	go func() {
+		defer workers.Done()
+		ledgerService.RunPendingTransfersExpirer(workersCtx)
+	}()
+
+	// Применяем запланированные смены цен
+	go func() {
+		defer workers.Done()
+		merchService.RunScheduledPrices(workersCtx, time.Duration(cfg.Catalog.ScheduleInterval)*time.Second)
+	}()
 
 	// Создаем хэндлер
-	handler := api.NewHandler(userService, purchasesService, ledgerService)
+	handler := api.NewHandler(userService, purchasesService, ledgerService, merchService, wishlistService, orderService, promotionService, imageService, healthService, logger)
 
 	// Создаем роутер
 	router := api.RegisterRoutes(handler)
 
 	// Запускаем сервер
-	log.Fatal(http.ListenAndServe(":8080", router))
+	srv := api.NewServer(cfg.Server, router)
+	srv.ErrorLog = slog.NewLogLogger(logger.Handler(), slog.LevelWarn)
+	logger.Info("Listening", "addr", srv.Addr, "tls", cfg.Server.TLS.CertFile != "")
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- api.ListenAndServe(srv, cfg.Server)
+	}()
+
+	// Метрики слушают отдельный порт: в публичном API их нет
+	metricsSrv := api.NewMetricsServer(cfg.Metrics)
+	if metricsSrv != nil {
+		metricsSrv.ErrorLog = srv.ErrorLog
+		logger.Info("Serving metrics", "addr", metricsSrv.Addr)
+		go func() {
+			if err := metricsSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics server failed", "error", err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Server failed", "error", err)
+		}
+	case <-ctx.Done():
+		logger.Info("Shutting down, waiting for in-flight requests", "timeout_seconds", cfg.Server.ShutdownTimeout)
+	}
+	stop()
+	healthService.SetShuttingDown()
+
+	// Перестаем принимать соединения и ждем, пока текущие запросы (переводы, покупки) закоммитят транзакции
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Graceful shutdown timed out, closing remaining connections", "error", err)
+		srv.Close()
+	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			metricsSrv.Close()
+		}
+	}
+
+	stopWorkers()
+	workers.Wait()
+	userService.Close()
+
+	// Пул закрываем последним: Close ждет возврата всех соединений
+	dbPool.Close()
+
+	// Досылаем спаны, накопленные в батчере; shutdownCtx к этому моменту мог истечь
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Warn("Failed to flush traces", "error", err)
+	}
+	logger.Info("Server stopped")
 }
@@ -12,40 +12,187 @@ type DatabaseConfig struct {
 	Port          int    `yaml:"port"`
 	User          string `yaml:"user"`
 	Password      string `yaml:"password"`
-	Dbname        string `yaml:"dbname"`
+	Dbname        string `yaml:"dbname" env:"DATABASE_NAME"`
 	Sslmode       string `yaml:"sslmode"`
 	Schema		  string `yaml:"schema"`
+	Seed          bool   `yaml:"seed"` // заполнить базу стартовым каталогом после миграций
+}
+
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
 }
 
 type ServerConfig struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host      string `yaml:"host"`
+	Port      int    `yaml:"port"`
 	SecretKey string `yaml:"secret_key"`
+	// Таймауты в секундах, 0 - без таймаута
+	ReadTimeout       int       `yaml:"read_timeout"`
+	ReadHeaderTimeout int       `yaml:"read_header_timeout"`
+	WriteTimeout      int       `yaml:"write_timeout"`
+	IdleTimeout       int       `yaml:"idle_timeout"`
+	MaxHeaderBytes    int       `yaml:"max_header_bytes"` // в килобайтах
+	TLS               TLSConfig `yaml:"tls"`              // пусто - обычный HTTP
+	HTTP2             bool      `yaml:"http2"`            // только вместе с TLS
+	// Сколько секунд ждать завершения текущих запросов при остановке
+	ShutdownTimeout int `yaml:"shutdown_timeout"`
 }
 
+
 type JwtConfig struct {
 	SecretKey string `yaml:"secret_key"`
 	Expiration int `yaml:"expiration"`
 }
 
+type TransfersConfig struct {
+	EscrowTTL      int `yaml:"escrow_ttl"`      // в минутах
+	ExpireInterval int `yaml:"expire_interval"` // в секундах
+}
+
+type PurchasesConfig struct {
+	ReturnWindow int `yaml:"return_window"` // в днях
+}
+
+type OrdersConfig struct {
+	Offices []string `yaml:"offices"` // офисы выдачи, первый - по умолчанию
+}
+
+type LocalStorageConfig struct {
+	Dir     string `yaml:"dir"`
+	BaseURL string `yaml:"base_url"` // префикс URL, по которому сервер раздает файлы
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	PublicURL string `yaml:"public_url"` // по умолчанию {endpoint}/{bucket}
+}
+
+type StorageConfig struct {
+	Driver string             `yaml:"driver"` // local или s3
+	Local  LocalStorageConfig `yaml:"local"`
+	S3     S3Config           `yaml:"s3"`
+}
+
+type ImagesConfig struct {
+	MaxSize       int `yaml:"max_size"`       // в килобайтах
+	ThumbnailSize int `yaml:"thumbnail_size"` // сторона миниатюры в пикселях
+}
+
+type CatalogConfig struct {
+	ScheduleInterval int `yaml:"schedule_interval"` // в секундах
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn или error
+	Format string `yaml:"format"` // text или json
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`     // otlp, stdout или пусто - трассировка выключена
+	Endpoint    string  `yaml:"endpoint"`     // host:port OTLP/HTTP коллектора
+	Insecure    bool    `yaml:"insecure"`     // слать в коллектор без TLS
+	SampleRatio float64 `yaml:"sample_ratio"` // доля трассируемых запросов от 0 до 1
+	ServiceName string  `yaml:"service_name"`
+}
+
+// MetricsConfig - отдельный листенер для /metrics, чтобы метрики не были видны через публичный API.
+type MetricsConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"` // 0 - /metrics не отдается
+}
+
+// Значения окружения
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
 type Config struct {
-	Database DatabaseConfig `yaml:"database"`
-	Server   ServerConfig   `yaml:"server"`
-	Jwt JwtConfig `yaml:"jwt"`
+	Environment string          `yaml:"environment"` // development или production
+	Database    DatabaseConfig  `yaml:"database"`
+	Server      ServerConfig    `yaml:"server"`
+	Jwt         JwtConfig       `yaml:"jwt"`
+	Transfers   TransfersConfig `yaml:"transfers"`
+	Purchases   PurchasesConfig `yaml:"purchases"`
+	Orders      OrdersConfig    `yaml:"orders"`
+	Storage     StorageConfig   `yaml:"storage"`
+	Images      ImagesConfig    `yaml:"images"`
+	Catalog     CatalogConfig   `yaml:"catalog"`
+	Log         LogConfig       `yaml:"log"`
+	Tracing     TracingConfig   `yaml:"tracing"`
+	Metrics     MetricsConfig   `yaml:"metrics"`
 }
 
+// LoadConfig читает конфиг из filename, поверх него применяет переменные окружения
+// (см. applyEnv) и проверяет результат. Пустой filename - конфиг только из окружения.
 func LoadConfig(filename string) (*Config, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("could not open config file: %v", err)
+	config := defaults()
+
+	if filename != "" {
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, fmt.Errorf("could not open config file: %w", err)
+		}
+		defer file.Close()
+
+		decoder := yaml.NewDecoder(file)
+		if err := decoder.Decode(&config); err != nil {
+			return nil, fmt.Errorf("could not decode config file %s: %w", filename, err)
+		}
 	}
-	defer file.Close()
 
-	var config Config
-	decoder := yaml.NewDecoder(file)
-	err = decoder.Decode(&config)
-	if err != nil {
-		return nil, fmt.Errorf("could not decode config file: %v", err)
+	if err := applyEnv(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 	return &config, nil
 }
+
+// defaults - значения, которые не обязательно указывать ни в файле, ни в окружении.
+func defaults() Config {
+	return Config{
+		Environment: EnvDevelopment,
+		Database: DatabaseConfig{
+			Port:    5432,
+			Sslmode: "disable",
+			Schema:  "MerchStore",
+		},
+		Server: ServerConfig{
+			Host:              "0.0.0.0",
+			Port:              8080,
+			ReadTimeout:       15,
+			ReadHeaderTimeout: 5,
+			WriteTimeout:      30,
+			IdleTimeout:       120,
+			MaxHeaderBytes:    1024,
+			HTTP2:             true,
+			ShutdownTimeout:   30,
+		},
+		Jwt: JwtConfig{
+			Expiration: 24,
+		},
+		Storage: StorageConfig{
+			Driver: "local",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "localhost:4318",
+			SampleRatio: 1,
+			ServiceName: "merch-store",
+		},
+		Metrics: MetricsConfig{
+			Host: "0.0.0.0",
+			Port: 9090,
+		},
+	}
+}
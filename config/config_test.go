@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+database:
+  host: localhost
+  user: postgres
+  dbname: merch_system
+jwt:
+  secret_key: changeme
+orders:
+  offices:
+    - Москва
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, EnvDevelopment, cfg.Environment)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, 9090, cfg.Metrics.Port)
+	assert.Equal(t, "local", cfg.Storage.Driver)
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	t.Setenv("DATABASE_HOST", "postgres")
+	t.Setenv("DATABASE_PORT", "6432")
+	t.Setenv("DATABASE_NAME", "merch_test")
+	t.Setenv("DATABASE_SEED", "true")
+	t.Setenv("STORAGE_S3_ACCESS_KEY", "key")
+	t.Setenv("ORDERS_OFFICES", "Казань, Санкт-Петербург")
+
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres", cfg.Database.Host)
+	assert.Equal(t, 6432, cfg.Database.Port)
+	assert.Equal(t, "merch_test", cfg.Database.Dbname)
+	assert.True(t, cfg.Database.Seed)
+	assert.Equal(t, "key", cfg.Storage.S3.AccessKey)
+	assert.Equal(t, []string{"Казань", "Санкт-Петербург"}, cfg.Orders.Offices)
+}
+
+func TestLoadConfig_InvalidEnvValue(t *testing.T) {
+	t.Setenv("DATABASE_PORT", "five")
+
+	_, err := LoadConfig(writeConfig(t, testConfig))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DATABASE_PORT")
+}
+
+func TestLoadConfig_RejectsDefaultSecretInProduction(t *testing.T) {
+	t.Setenv("ENVIRONMENT", EnvProduction)
+
+	_, err := LoadConfig(writeConfig(t, testConfig))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jwt.secret_key")
+
+	t.Setenv("JWT_SECRET_KEY", "a-real-secret")
+	_, err = LoadConfig(writeConfig(t, testConfig))
+	assert.NoError(t, err)
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, "server:\n  port: 0\n"))
+	require.Error(t, err)
+
+	for _, field := range []string{"database.host", "database.user", "database.dbname", "server.port", "jwt.secret_key"} {
+		assert.True(t, strings.Contains(err.Error(), field), "missing problem for %s", field)
+	}
+}
+
+func TestValidate_TLSNeedsCertAndKey(t *testing.T) {
+	t.Setenv("SERVER_TLS_CERT_FILE", "/etc/merch/tls.crt")
+
+	_, err := LoadConfig(writeConfig(t, testConfig))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.tls")
+
+	t.Setenv("SERVER_TLS_KEY_FILE", "/etc/merch/tls.key")
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+	assert.True(t, cfg.Server.HTTP2)
+}
+
+func TestValidate_MetricsPortSeparateFromServer(t *testing.T) {
+	t.Setenv("METRICS_PORT", "8080")
+
+	_, err := LoadConfig(writeConfig(t, testConfig))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics.port")
+
+	// 0 выключает листенер метрик
+	t.Setenv("METRICS_PORT", "0")
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Metrics.Port)
+}
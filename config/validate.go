@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultSecret - заглушка из config.yml, с которой нельзя запускаться в production.
+const defaultSecret = "changeme"
+
+// Validate проверяет обязательные поля и возвращает все найденные проблемы сразу.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Environment != EnvDevelopment && c.Environment != EnvProduction {
+		add("environment must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Environment)
+	}
+
+	if c.Database.Host == "" {
+		add("database.host is required (DATABASE_HOST)")
+	}
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		add("database.port must be between 1 and 65535 (DATABASE_PORT)")
+	}
+	if c.Database.User == "" {
+		add("database.user is required (DATABASE_USER)")
+	}
+	if c.Database.Dbname == "" {
+		add("database.dbname is required (DATABASE_NAME)")
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		add("server.port must be between 1 and 65535 (SERVER_PORT)")
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		add("server timeouts must not be negative")
+	}
+	if c.Server.MaxHeaderBytes < 0 {
+		add("server.max_header_bytes must not be negative (SERVER_MAX_HEADER_BYTES)")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		add("server.shutdown_timeout must be positive (SERVER_SHUTDOWN_TIMEOUT)")
+	}
+	if (c.Server.TLS.CertFile == "") != (c.Server.TLS.KeyFile == "") {
+		add("server.tls.cert_file and server.tls.key_file must be set together")
+	}
+
+	if c.Jwt.SecretKey == "" {
+		add("jwt.secret_key is required (JWT_SECRET_KEY)")
+	} else if c.Environment == EnvProduction && c.Jwt.SecretKey == defaultSecret {
+		add("jwt.secret_key must be changed from the default %q in production (JWT_SECRET_KEY)", defaultSecret)
+	}
+	if c.Jwt.Expiration <= 0 {
+		add("jwt.expiration must be positive (JWT_EXPIRATION)")
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+	case "s3":
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" {
+			add("storage.s3.endpoint and storage.s3.bucket are required for the s3 driver")
+		}
+	default:
+		add("storage.driver must be local or s3, got %q (STORAGE_DRIVER)", c.Storage.Driver)
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		add("log.level must be debug, info, warn or error, got %q (LOG_LEVEL)", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		add("log.format must be text or json, got %q (LOG_FORMAT)", c.Log.Format)
+	}
+
+	switch c.Tracing.Exporter {
+	case "", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint == "" {
+			add("tracing.endpoint is required for the otlp exporter (TRACING_ENDPOINT)")
+		}
+	default:
+		add("tracing.exporter must be otlp, stdout or empty, got %q (TRACING_EXPORTER)", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be between 0 and 1 (TRACING_SAMPLE_RATIO)")
+	}
+
+	if c.Metrics.Port < 0 || c.Metrics.Port > 65535 {
+		add("metrics.port must be between 0 and 65535 (METRICS_PORT)")
+	} else if c.Metrics.Port != 0 && c.Metrics.Port == c.Server.Port {
+		add("metrics.port must differ from server.port, /metrics is not served on the public API (METRICS_PORT)")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid config:\n  - " + strings.Join(problems, "\n  - "))
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,20 +16,33 @@ type CacheItem struct {
 type Cache struct {
 	data map[string]CacheItem
 	mu   sync.RWMutex
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
 }
 
 // NewCache создает новый кэш.
 func NewCache() *Cache {
 	return &Cache{
 		data: make(map[string]CacheItem),
+		stop: make(chan struct{}),
 	}
 }
 
 // cleanupExpiredItems периодически очищает элементы, срок действия которых истек.
+// Возвращается после вызова Stop.
 func (c *Cache) СleanupExpiredItems() {
 	ticker := time.NewTicker(5 * time.Minute) // Каждые 5 минут чекаем кэш
+	defer ticker.Stop()
 	for {
-		<-ticker.C
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
 		c.mu.Lock()
 		for key, item := range c.data {
 			if time.Now().After(item.ExpiresAt) {
@@ -39,6 +53,11 @@ func (c *Cache) СleanupExpiredItems() {
 	}
 }
 
+// Stop останавливает очистку кэша. Повторные вызовы ничего не делают.
+func (c *Cache) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
 // Set добавляет элемент в кэш с заданным TTL.
 func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
 	c.mu.Lock()
@@ -55,11 +74,18 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	defer c.mu.RUnlock()
 	item, exists := c.data[key]
 	if !exists || time.Now().After(item.ExpiresAt) {
+		c.misses.Add(1)
 		return nil, false
 	}
+	c.hits.Add(1)
 	return item.Value, true
 }
 
+// Stats возвращает число попаданий и промахов Get с момента создания кэша.
+func (c *Cache) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
 // Delete удаляет элемент из кэша.
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
@@ -3,14 +3,17 @@ package database
 import (
     "context"
     "fmt"
-    "log"
+    "log/slog"
 
     "github.com/jackc/pgx/v4/pgxpool"
     "EmployeeMerchStore/config"
+    "EmployeeMerchStore/internal/logging"
+    "EmployeeMerchStore/internal/tracing"
 )
 
-// InitDB инициализирует подключение к базе данных
-func InitDB(cfg *config.Config) (*pgxpool.Pool, error) {
+// InitDB инициализирует подключение к базе данных.
+// Запросы pgx логируются через logger на уровне debug и, если включена трассировка, становятся спанами.
+func InitDB(cfg *config.Config, logger *slog.Logger) (*pgxpool.Pool, error) {
     dbURL := fmt.Sprintf(
         "postgres://%s:%s@%s:%d/%s?sslmode=%s&search_path=%s",
         cfg.Database.User,
@@ -22,11 +25,18 @@ func InitDB(cfg *config.Config) (*pgxpool.Pool, error) {
         cfg.Database.Schema,
     )
 
-    pool, err := pgxpool.Connect(context.Background(), dbURL)
+    poolConfig, err := pgxpool.ParseConfig(dbURL)
+    if err != nil {
+        return nil, fmt.Errorf("invalid database config: %w", err)
+    }
+    poolConfig.ConnConfig.Logger = tracing.PgxLogger(logging.PgxLogger(logger))
+    poolConfig.ConnConfig.LogLevel = max(logging.PgxLogLevel(logger), tracing.PgxLogLevel())
+
+    pool, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
     if err != nil {
         return nil, fmt.Errorf("unable to connect to database: %w", err)
     }
 
-    log.Println("Connected to database")
+    logger.Info("Connected to database", "host", cfg.Database.Host, "dbname", cfg.Database.Dbname)
     return pool, nil
 }
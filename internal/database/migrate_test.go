@@ -0,0 +1,70 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_SortsAndPairs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_stock.up.sql":    {Data: []byte("ALTER TABLE merch ADD COLUMN stock INTEGER;")},
+		"0002_add_stock.down.sql":  {Data: []byte("ALTER TABLE merch DROP COLUMN stock;")},
+		"0001_create_merch.up.sql": {Data: []byte("CREATE TABLE merch (id SERIAL);")},
+		"README.md":                {Data: []byte("not a migration")},
+	}
+
+	migrations, err := loadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "create_merch", migrations[0].Name)
+	assert.Empty(t, migrations[0].Down)
+	assert.Equal(t, 2, migrations[1].Version)
+	assert.Equal(t, "ALTER TABLE merch DROP COLUMN stock;", migrations[1].Down)
+	assert.Len(t, migrations[1].Checksum, 64)
+}
+
+func TestLoadMigrations_ChecksumTracksUpFile(t *testing.T) {
+	load := func(up string) string {
+		migrations, err := loadMigrations(fstest.MapFS{"0001_a.up.sql": {Data: []byte(up)}})
+		require.NoError(t, err)
+		return migrations[0].Checksum
+	}
+
+	assert.Equal(t, load("SELECT 1;"), load("SELECT 1;"))
+	assert.NotEqual(t, load("SELECT 1;"), load("SELECT 2;"))
+}
+
+func TestMigrationLockID_Stable(t *testing.T) {
+	// Ключ должен совпадать у всех версий, иначе старая и новая реплика мигрируют одновременно
+	assert.Equal(t, int64(6865844714840838526), migrationLockID)
+	assert.Equal(t, advisoryLockKey(migrationLockName), migrationLockID)
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"bad name":          {"create_merch.sql": {Data: []byte("SELECT 1;")}},
+		"duplicate version": {"0001_a.up.sql": {Data: []byte("SELECT 1;")}, "0001_b.up.sql": {Data: []byte("SELECT 1;")}},
+		"down without up":   {"0001_a.down.sql": {Data: []byte("SELECT 1;")}},
+	}
+	for name, fsys := range cases {
+		_, err := loadMigrations(fsys)
+		assert.Error(t, err, name)
+	}
+}
+
+// Все миграции репозитория должны разбираться и иметь down-файл.
+func TestLoadMigrations_RepoMigrations(t *testing.T) {
+	migrations, err := loadMigrations(Migrations())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "versions must be sequential")
+		assert.NotEmpty(t, migration.Down, "migration %04d_%s has no down file", migration.Version, migration.Name)
+	}
+}
@@ -0,0 +1,2 @@
+-- Схему не удаляем: в ней же лежит schema_migrations
+DROP TABLE IF EXISTS "MerchStore".users;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".merch (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE,
+    price INTEGER NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merch_name_price ON "MerchStore".merch (name) INCLUDE (price);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "MerchStore".idx_ledger_user_created_at;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".pending_transfers (
+    id SERIAL PRIMARY KEY,
+    from_user_id TEXT NOT NULL,
+    to_user_id TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'accepted', 'declined', 'cancelled', 'expired'
+    created_at TIMESTAMP DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    CONSTRAINT fk_from_user FOREIGN KEY (from_user_id) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_to_user FOREIGN KEY (to_user_id) REFERENCES "MerchStore".users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_expires_at ON "MerchStore".pending_transfers (expires_at) WHERE status = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE "MerchStore".users DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1 @@
+ALTER TABLE "MerchStore".users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".transfer_reversals (
+    id SERIAL PRIMARY KEY,
+    transfer_id INTEGER NOT NULL, -- id записи 'transfer_out' в ledger
+    from_user_id TEXT NOT NULL,   -- отправитель исходного перевода
+    to_user_id TEXT NOT NULL,     -- получатель исходного перевода
+    amount INTEGER NOT NULL,
+    reversed_by TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    allow_negative BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT uq_transfer_reversal UNIQUE (transfer_id),
+    CONSTRAINT fk_transfer FOREIGN KEY (transfer_id) REFERENCES "MerchStore".ledger(id),
+    CONSTRAINT fk_reversed_by FOREIGN KEY (reversed_by) REFERENCES "MerchStore".users(id)
+);
@@ -0,0 +1 @@
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,2 @@
+-- NULL - неограниченный запас
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS stock INTEGER CHECK (stock >= 0);
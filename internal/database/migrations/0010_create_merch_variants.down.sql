@@ -0,0 +1,3 @@
+-- uq_user_merch не возвращаем: покупки разных вариантов уже могли дать несколько строк на товар
+ALTER TABLE "MerchStore".purchases DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS "MerchStore".merch_variants;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".merch_variants (
+    id SERIAL PRIMARY KEY,
+    merch_id INTEGER NOT NULL,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    size VARCHAR(8),    -- 'S', 'M', 'L', 'XL', 'XXL'
+    colour VARCHAR(50),
+    price_delta INTEGER NOT NULL DEFAULT 0, -- надбавка к цене мерча
+    stock INTEGER CHECK (stock >= 0),       -- NULL - неограниченный запас
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_merch_variants_merch_id ON "MerchStore".merch_variants (merch_id);
+
+-- Вариант покупки, NULL - мерч без вариантов
+ALTER TABLE "MerchStore".purchases ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES "MerchStore".merch_variants(id);
+ALTER TABLE "MerchStore".purchases DROP CONSTRAINT IF EXISTS uq_user_merch;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "MerchStore".notifications;
+DROP TABLE IF EXISTS "MerchStore".wishlist;
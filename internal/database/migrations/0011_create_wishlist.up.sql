@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".wishlist (
+    user_id TEXT NOT NULL,
+    merch_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    PRIMARY KEY (user_id, merch_id),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_merch_id ON "MerchStore".wishlist (merch_id);
+
+CREATE TABLE IF NOT EXISTS "MerchStore".notifications (
+    id SERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    kind VARCHAR(50) NOT NULL, -- 'restock', 'price_drop'
+    merch_id INTEGER,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    read_at TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON "MerchStore".notifications (user_id, created_at DESC);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".gifts (
+    id SERIAL PRIMARY KEY,
+    from_user_id TEXT NOT NULL,
+    to_user_id TEXT NOT NULL,
+    merch_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    purchase_id INTEGER NOT NULL, -- строка инвентаря получателя
+    message VARCHAR(500),
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_from_user FOREIGN KEY (from_user_id) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_to_user FOREIGN KEY (to_user_id) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id),
+    CONSTRAINT fk_variant FOREIGN KEY (variant_id) REFERENCES "MerchStore".merch_variants(id),
+    CONSTRAINT fk_purchase FOREIGN KEY (purchase_id) REFERENCES "MerchStore".purchases(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_gifts_to_user_created_at ON "MerchStore".gifts (to_user_id, created_at DESC);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".orders (
+    id SERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,  -- кому выдать мерч
+    paid_by TEXT NOT NULL,  -- кто заплатил, у подарка отличается от user_id
+    purchase_id INTEGER NOT NULL,
+    merch_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_price INTEGER NOT NULL,
+    office VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'placed',
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_paid_by FOREIGN KEY (paid_by) REFERENCES "MerchStore".users(id),
+    CONSTRAINT fk_purchase FOREIGN KEY (purchase_id) REFERENCES "MerchStore".purchases(id),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id),
+    CONSTRAINT fk_variant FOREIGN KEY (variant_id) REFERENCES "MerchStore".merch_variants(id),
+    CONSTRAINT chk_order_status CHECK (status IN ('placed', 'packed', 'ready_for_pickup', 'delivered', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_user_status ON "MerchStore".orders (user_id, status);
+CREATE INDEX IF NOT EXISTS idx_orders_status_created_at ON "MerchStore".orders (status, created_at);
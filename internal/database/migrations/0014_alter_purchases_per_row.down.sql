@@ -0,0 +1,5 @@
+-- Строки покупок обратно не схлопываем: у каждой своя цена, агрегировать без потерь нельзя
+DROP VIEW IF EXISTS "MerchStore".user_inventory;
+DROP INDEX IF EXISTS "MerchStore".idx_purchases_user_merch_variant;
+ALTER TABLE "MerchStore".purchases DROP COLUMN IF EXISTS returned_quantity;
+ALTER TABLE "MerchStore".purchases DROP COLUMN IF EXISTS unit_price;
@@ -0,0 +1,26 @@
+-- Каждая покупка - отдельная строка с ценой на момент покупки.
+-- Инвентарь пользователя собирается из них представлением user_inventory.
+DROP INDEX IF EXISTS "MerchStore".uq_purchases_user_merch_variant;
+
+ALTER TABLE "MerchStore".purchases ADD COLUMN IF NOT EXISTS unit_price INTEGER;
+ALTER TABLE "MerchStore".purchases ADD COLUMN IF NOT EXISTS returned_quantity INTEGER NOT NULL DEFAULT 0;
+
+-- Для старых агрегированных строк цена покупки неизвестна, берем текущую
+UPDATE "MerchStore".purchases p
+SET unit_price = m.price + COALESCE(
+        (SELECT v.price_delta FROM "MerchStore".merch_variants v WHERE v.id = p.variant_id), 0)
+FROM "MerchStore".merch m
+WHERE p.unit_price IS NULL AND m.id = p.merch_id;
+
+ALTER TABLE "MerchStore".purchases ALTER COLUMN unit_price SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_purchases_user_merch_variant
+    ON "MerchStore".purchases (user_id, merch_id, variant_id);
+
+CREATE OR REPLACE VIEW "MerchStore".user_inventory AS
+SELECT user_id, merch_id, variant_id,
+       SUM(quantity - returned_quantity)::integer AS quantity,
+       MAX(purchased_at) AS last_purchased_at
+FROM "MerchStore".purchases
+GROUP BY user_id, merch_id, variant_id
+HAVING SUM(quantity - returned_quantity) > 0;
@@ -0,0 +1,6 @@
+ALTER TABLE "MerchStore".ledger DROP COLUMN IF EXISTS discount;
+ALTER TABLE "MerchStore".purchases DROP COLUMN IF EXISTS promo_code_id;
+ALTER TABLE "MerchStore".purchases DROP COLUMN IF EXISTS promotion_id;
+ALTER TABLE "MerchStore".purchases DROP COLUMN IF EXISTS discount;
+DROP TABLE IF EXISTS "MerchStore".promo_codes;
+DROP TABLE IF EXISTS "MerchStore".promotions;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    merch_id INTEGER, -- NULL - акция на весь магазин
+    discount_type VARCHAR(10) NOT NULL,
+    discount_value INTEGER NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id),
+    CONSTRAINT chk_promotion_discount CHECK (
+        discount_value > 0 AND (discount_type = 'fixed' OR (discount_type = 'percent' AND discount_value <= 100))
+    ),
+    CONSTRAINT chk_promotion_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_period ON "MerchStore".promotions (starts_at, ends_at);
+
+CREATE TABLE IF NOT EXISTS "MerchStore".promo_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    merch_id INTEGER, -- NULL - код действует на любой мерч
+    discount_type VARCHAR(10) NOT NULL,
+    discount_value INTEGER NOT NULL,
+    max_uses INTEGER CHECK (max_uses > 0), -- NULL - без ограничений, 1 - одноразовый
+    used_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id),
+    CONSTRAINT chk_promo_code_discount CHECK (
+        discount_value > 0 AND (discount_type = 'fixed' OR (discount_type = 'percent' AND discount_value <= 100))
+    )
+);
+
+-- Скидка запоминается на покупке и в ledger
+ALTER TABLE "MerchStore".purchases ADD COLUMN IF NOT EXISTS discount INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "MerchStore".purchases ADD COLUMN IF NOT EXISTS promotion_id INTEGER REFERENCES "MerchStore".promotions(id);
+ALTER TABLE "MerchStore".purchases ADD COLUMN IF NOT EXISTS promo_code_id INTEGER REFERENCES "MerchStore".promo_codes(id);
+ALTER TABLE "MerchStore".ledger ADD COLUMN IF NOT EXISTS discount INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE "MerchStore".promotions DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS "MerchStore".merch_tags;
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS "MerchStore".categories;
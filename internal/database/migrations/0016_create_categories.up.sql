@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".categories (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL
+);
+
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES "MerchStore".categories(id);
+CREATE INDEX IF NOT EXISTS idx_merch_category_id ON "MerchStore".merch (category_id);
+
+CREATE TABLE IF NOT EXISTS "MerchStore".merch_tags (
+    merch_id INTEGER NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (merch_id, tag),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_merch_tags_tag ON "MerchStore".merch_tags (tag);
+
+-- Акция может действовать на целую категорию
+ALTER TABLE "MerchStore".promotions ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES "MerchStore".categories(id);
@@ -0,0 +1,2 @@
+-- Файлы картинок в хранилище остаются, их нужно удалить отдельно
+DROP TABLE IF EXISTS "MerchStore".merch_images;
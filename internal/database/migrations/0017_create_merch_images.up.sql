@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS "MerchStore".merch_images (
+    id SERIAL PRIMARY KEY,
+    merch_id INTEGER NOT NULL,
+    key VARCHAR(255) NOT NULL,           -- ключ оригинала в хранилище
+    thumbnail_key VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_merch_images_merch_id ON "MerchStore".merch_images (merch_id);
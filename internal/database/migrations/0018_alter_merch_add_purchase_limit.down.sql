@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "MerchStore".idx_purchases_user_merch_purchased_at;
+ALTER TABLE "MerchStore".merch DROP CONSTRAINT IF EXISTS chk_merch_limit_period;
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS limit_period;
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS purchase_limit;
@@ -0,0 +1,11 @@
+-- Ограничение покупок одного товара на сотрудника: не больше purchase_limit штук за limit_period.
+-- NULL - без ограничений.
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS purchase_limit INTEGER CHECK (purchase_limit > 0);
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS limit_period VARCHAR(10) NOT NULL DEFAULT 'lifetime';
+
+ALTER TABLE "MerchStore".merch DROP CONSTRAINT IF EXISTS chk_merch_limit_period;
+ALTER TABLE "MerchStore".merch ADD CONSTRAINT chk_merch_limit_period
+    CHECK (limit_period IN ('lifetime', 'month', 'quarter', 'year'));
+
+CREATE INDEX IF NOT EXISTS idx_purchases_user_merch_purchased_at
+    ON "MerchStore".purchases (user_id, merch_id, purchased_at);
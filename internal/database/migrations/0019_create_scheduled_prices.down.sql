@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "MerchStore".scheduled_prices;
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS available_until;
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS available_from;
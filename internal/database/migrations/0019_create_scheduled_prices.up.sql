@@ -0,0 +1,16 @@
+-- Окно доступности товара: вне его товар не виден в каталоге и не продается. NULL - без ограничения.
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS available_from TIMESTAMP;
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS available_until TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS "MerchStore".scheduled_prices (
+    id SERIAL PRIMARY KEY,
+    merch_id INTEGER NOT NULL,
+    price INTEGER NOT NULL CHECK (price > 0),
+    effective_at TIMESTAMP NOT NULL,
+    applied_at TIMESTAMP, -- NULL - еще не применена
+    created_at TIMESTAMP DEFAULT now(),
+    CONSTRAINT fk_merch FOREIGN KEY (merch_id) REFERENCES "MerchStore".merch(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_prices_pending
+    ON "MerchStore".scheduled_prices (effective_at) WHERE applied_at IS NULL;
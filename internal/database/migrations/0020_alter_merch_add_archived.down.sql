@@ -0,0 +1,4 @@
+-- Упадет, если у архивного и активного товара совпадают имена: такие дубли нужно сначала переименовать
+DROP INDEX IF EXISTS "MerchStore".uq_merch_active_name;
+ALTER TABLE "MerchStore".merch ADD CONSTRAINT merch_name_key UNIQUE (name);
+ALTER TABLE "MerchStore".merch DROP COLUMN IF EXISTS archived_at;
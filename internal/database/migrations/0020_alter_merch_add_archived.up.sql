@@ -0,0 +1,6 @@
+-- Товар не удаляется, а архивируется: покупки и история ссылаются на него по merch_id.
+ALTER TABLE "MerchStore".merch ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP; -- NULL - товар в продаже
+
+-- Имя уникально только среди активных товаров, чтобы после архивации можно было завести товар с тем же именем
+ALTER TABLE "MerchStore".merch DROP CONSTRAINT IF EXISTS merch_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_merch_active_name ON "MerchStore".merch (name) WHERE archived_at IS NULL;
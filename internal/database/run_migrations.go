@@ -2,37 +2,33 @@ package database
 
 import (
 	"context"
-	"fmt"
-	"io/ioutil"
-	"log"
+	"embed"
+	"io/fs"
+	"log/slog"
 
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
-func RunMigrations(ctx context.Context, conn *pgxpool.Pool) error {
-	// Читаем SQL файлы миграций
-	files := []string{
-		"internal/database/migrations/create_user.sql",
-		"internal/database/migrations/create_merch.sql",
-		"internal/database/migrations/create_ledger.sql",
-		"internal/database/migrations/create_idx_lastLedger.sql",
-		"internal/database/migrations/create_purchases.sql",
-	}
-	for _, file := range files {
-		// Читаем содержимое файла
-		sqlContent, err := ioutil.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read SQL file %s: %w", file, err)
-		}
-
-		// Выполняем SQL запрос
-		_, err = conn.Exec(ctx, string(sqlContent)) // Передаем ctx
-		if err != nil {
-			return fmt.Errorf("failed to execute migration %s: %w", file, err)
-		}
+// Миграции вшиты в бинарник, поэтому сервер не зависит от рабочей директории.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
 
-		log.Printf("Successfully executed migration: %s", file)
+// Migrations возвращает вшитые файлы миграций.
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		panic(err) // каталог вшит при сборке, ошибки быть не может
 	}
+	return sub
+}
 
-	return nil
+// RunMigrations применяет новые миграции.
+// Миграции, уже записанные в schema_migrations, повторно не выполняются.
+func RunMigrations(ctx context.Context, conn *pgxpool.Pool, logger *slog.Logger) error {
+	migrator, err := NewMigrator(conn, Migrations(), logger)
+	if err != nil {
+		return err
+	}
+	return migrator.Up(ctx)
 }
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Стартовые данные отделены от схемы: миграции создают таблицы, а заполнять их
+// каталогом нужно только в dev-окружении и на тестовых стендах.
+//
+//go:embed seeds/*.sql
+var seedsFS embed.FS
+
+// Seed заполняет базу стартовыми данными. Запускается после миграций и
+// идемпотентен: уже существующие записи не трогает.
+func Seed(ctx context.Context, conn *pgxpool.Pool, logger *slog.Logger) error {
+	files, err := fs.Glob(seedsFS, "seeds/*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list seeds: %w", err)
+	}
+	sort.Strings(files)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, file := range files {
+		content, err := seedsFS.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read seed %s: %w", file, err)
+		}
+		if _, err := tx.Exec(ctx, string(content)); err != nil {
+			return fmt.Errorf("failed to execute seed %s: %w", file, err)
+		}
+		logger.InfoContext(ctx, "Applied seed", "file", file)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+-- Стартовый каталог: категории и мерч.
+-- Повторный запуск ничего не меняет: существующие категории и товары (в том числе архивные) пропускаются.
+INSERT INTO "MerchStore".categories (slug, name) VALUES
+    ('apparel', 'Одежда'),
+    ('accessories', 'Аксессуары'),
+    ('stationery', 'Канцелярия')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO "MerchStore".merch (name, price, description, category_id)
+SELECT v.name, v.price, v.description, c.id
+FROM (VALUES
+    ('T-Shirt', 80, 'A cool t-shirt', 'apparel'),
+    ('cup', 20, 'A nice cup', 'accessories'),
+    ('book', 50, 'An interesting book', 'stationery'),
+    ('pen', 10, 'A pen', 'stationery'),
+    ('powerbank', 200, 'A powerbank', 'accessories'),
+    ('hoody', 300, 'A hoody', 'apparel'),
+    ('umbrella', 200, 'An umbrella', 'accessories'),
+    ('socks', 10, 'Socks', 'apparel'),
+    ('wallet', 50, 'A wallet', 'accessories'),
+    ('pink-hoody', 500, 'A pink hoody', 'apparel')
+) AS v(name, price, description, category)
+LEFT JOIN "MerchStore".categories c ON c.slug = v.category
+WHERE NOT EXISTS (SELECT 1 FROM "MerchStore".merch m WHERE m.name = v.name);
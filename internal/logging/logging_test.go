@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"EmployeeMerchStore/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew_AddsRequestIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(config.LogConfig{Level: "info", Format: "json"}, &buf)
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	logger.InfoContext(ctx, "Request", "status", 200)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-42", record["request_id"])
+	assert.Equal(t, float64(200), record["status"])
+}
+
+func TestNew_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(config.LogConfig{Level: "warn", Format: "text"}, &buf)
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	assert.Empty(t, buf.String())
+	logger.With("merch_id", 7).Warn("shown")
+	assert.Contains(t, buf.String(), "merch_id=7")
+}
+
+func TestNew_RejectsUnknownSettings(t *testing.T) {
+	_, err := New(config.LogConfig{Level: "loud", Format: "text"}, &bytes.Buffer{})
+	assert.Error(t, err)
+
+	_, err = New(config.LogConfig{Level: "info", Format: "xml"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestNew_AddsTraceIDFromSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(config.LogConfig{Level: "info", Format: "json"}, &buf)
+	require.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	logger.InfoContext(ctx, "Applied scheduled prices")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// PgxLogger пишет логи pgx через slog, поэтому запросы всех репозиториев
+// попадают в лог с request_id из ctx.
+func PgxLogger(logger *slog.Logger) pgx.Logger {
+	return pgx.LoggerFunc(func(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+		// pgx может звать логгер ради трассировки, даже когда запросы в лог не нужны
+		if !logger.Enabled(ctx, pgxLevel(level)) {
+			return
+		}
+		attrs := make([]slog.Attr, 0, len(data))
+		for k, v := range data {
+			// В аргументах бывают хэши паролей и прочие персональные данные
+			if k == "args" {
+				continue
+			}
+			attrs = append(attrs, slog.Any(k, v))
+		}
+		logger.LogAttrs(ctx, pgxLevel(level), msg, attrs...)
+	})
+}
+
+// PgxLogLevel - уровень, с которым pgx вообще вызывает логгер. Каждый запрос pgx
+// логирует на info, поэтому запросы пишем только при уровне debug.
+func PgxLogLevel(logger *slog.Logger) pgx.LogLevel {
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		return pgx.LogLevelInfo
+	}
+	return pgx.LogLevelWarn
+}
+
+// pgxLevel переводит уровни pgx в slog. Ошибки запросов - warn:
+// репозиторий вернет их вызывающему коду, и тот решит, насколько это серьезно.
+func pgxLevel(level pgx.LogLevel) slog.Level {
+	switch {
+	case level >= pgx.LogLevelInfo:
+		return slog.LevelDebug
+	default:
+		return slog.LevelWarn
+	}
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Middleware)
+	router.HandleFunc("/api/buy/{item}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not enough coins", http.StatusBadRequest)
+	})
+
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues("/api/buy/{item}", "GET", "400"))
+	for _, item := range []string{"t-shirt", "cup"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/buy/"+item, nil))
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(HTTPRequests.WithLabelValues("/api/buy/{item}", "GET", "400")))
+}
@@ -0,0 +1,50 @@
+// Package metrics описывает метрики Prometheus сервиса: HTTP, пул БД, кэш и бизнес-события.
+// Все метрики регистрируются в реестре по умолчанию и отдаются на /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "merch"
+
+var (
+	// HTTPRequestDuration - время обработки запроса по шаблону маршрута, например /api/buy/{item}.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"route", "method"})
+
+	// HTTPRequests - число запросов по маршруту и коду ответа.
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	// CoinsTransferred - сколько монет отправлено: direct - сразу, escrow - отложенным переводом.
+	CoinsTransferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_transferred_total",
+		Help:      "Coins sent between users.",
+	}, []string{"kind"})
+
+	// Purchases - успешные покупки и подарки по товару.
+	Purchases = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "purchases_total",
+		Help:      "Successful purchases by merch item.",
+	}, []string{"item"})
+
+	// FailedAuths - неудачные попытки аутентификации: wrong_password или invalid_token.
+	FailedAuths = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Failed authentication attempts by reason.",
+	}, []string{"reason"})
+)
@@ -0,0 +1,16 @@
+package models
+
+// Category - категория мерча: apparel, accessories, stationery и т.д.
+type Category struct {
+	ID   int    `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// MerchFilter - фильтр каталога. Пустые поля не ограничивают выборку.
+type MerchFilter struct {
+	Category      string // slug категории
+	Tag           string
+	AvailableOnly bool // только товары, которые можно купить сейчас
+	Archived      bool // архивные товары вместо активных
+}
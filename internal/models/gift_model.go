@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Gift struct {
+	ID         int       `json:"id"`
+	FromUserID string    `json:"from_user_id"`
+	FromUser   string    `json:"from_user"`
+	MerchID    int       `json:"merch_id"`
+	Name       string    `json:"name"`
+	VariantID  *int      `json:"variant_id,omitempty"`
+	SKU        string    `json:"sku,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
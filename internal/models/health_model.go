@@ -0,0 +1,8 @@
+package models
+
+// HealthCheck - результат одной проверки готовности сервиса.
+type HealthCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
@@ -2,6 +2,32 @@ package models
 
 import "time"
 
+// Типы движений в ledger
+const (
+	MovementTransferIn    = "transfer_in"
+	MovementTransferOut   = "transfer_out"
+	MovementPurchase      = "purchase"
+	MovementEscrowHold    = "escrow_hold"    // монеты отправителя заморожены до решения получателя
+	MovementEscrowIn      = "escrow_in"      // получатель принял отложенный перевод
+	MovementEscrowRelease = "escrow_release" // перевод отклонен, отменен или просрочен, монеты вернулись отправителю
+	MovementReversalIn    = "reversal_in"    // администратор отменил перевод, монеты вернулись отправителю
+	MovementReversalOut   = "reversal_out"   // администратор отменил перевод, монеты списаны у получателя
+	MovementRefund        = "refund"         // возврат мерча, reference_id - id покупки
+	MovementGiftSent      = "gift_sent"      // покупка мерча в подарок, reference_id - id подарка
+	MovementGiftReceived  = "gift_received"  // получен подарок, баланс получателя не меняется
+	MovementOrderRefund   = "order_refund"   // заказ отменен, монеты вернулись плательщику, reference_id - id заказа
+	MovementGrant         = "grant"          // начисление монет через merchctl
+)
+
+// Движения, которые увеличивают и уменьшают баланс пользователя.
+// gift_received сюда не входит: подарок не меняет баланс получателя.
+var (
+	CreditMovements = []string{MovementTransferIn, MovementEscrowIn, MovementEscrowRelease, MovementReversalIn,
+		MovementRefund, MovementOrderRefund, MovementGrant}
+	DebitMovements = []string{MovementTransferOut, MovementPurchase, MovementEscrowHold, MovementReversalOut,
+		MovementGiftSent}
+)
+
 type Ledger struct {
 	ID          int       `json:"id"`
 	UserID      string    `json:"user_id"`
@@ -9,5 +35,6 @@ type Ledger struct {
 	Amount      float64   `json:"amount"`
 	ReferenceID *int      `json:"reference_id,omitempty"` 
 	Reference_id_usr string `json:"reference_id_usr,omitempty"`// "Костыль"
+	Discount    int       `json:"discount,omitempty"` // скидка по акции или промокоду при покупке, у возврата - скидка возвращенной покупки
 	CreatedAt   time.Time `json:"created_at"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type MerchImage struct {
+	ID           int       `json:"id"`
+	MerchID      int       `json:"merch_id"`
+	Key          string    `json:"-"` // ключ оригинала в хранилище
+	ThumbnailKey string    `json:"-"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -2,10 +2,37 @@ package models
 
 import "time"
 
+// Периоды, за которые считается лимит покупок товара
+const (
+	LimitPeriodLifetime = "lifetime" // за все время
+	LimitPeriodMonth    = "month"
+	LimitPeriodQuarter  = "quarter"
+	LimitPeriodYear     = "year"
+)
+
+// IsLimitPeriod сообщает, известен ли период лимита.
+func IsLimitPeriod(period string) bool {
+	switch period {
+	case LimitPeriodLifetime, LimitPeriodMonth, LimitPeriodQuarter, LimitPeriodYear:
+		return true
+	}
+	return false
+}
+
 type Merch struct {
 	ID          int    	  `json:"id"`
 	Name        string    `json:"name"`
 	Price       int       `json:"price"`
 	Description string    `json:"description"`
+	Stock       *int      `json:"stock"` // nil - неограниченный запас
+	PurchaseLimit *int    `json:"purchase_limit,omitempty"` // сколько штук один сотрудник может купить за LimitPeriod
+	LimitPeriod string    `json:"limit_period,omitempty"`
+	AvailableFrom  *time.Time `json:"available_from,omitempty"` // nil - без ограничения
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"` // nil - товар в продаже
+	Category    string    `json:"category,omitempty"` // slug категории
+	Tags        []string  `json:"tags"`
+	Variants    []MerchVariant `json:"variants,omitempty"`
+	Images      []MerchImage   `json:"images,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
-}
\ No newline at end of file
+}
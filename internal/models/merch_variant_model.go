@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Допустимые размеры вариантов мерча
+var VariantSizes = []string{"S", "M", "L", "XL", "XXL"}
+
+type MerchVariant struct {
+	ID         int       `json:"id"`
+	MerchID    int       `json:"merch_id"`
+	SKU        string    `json:"sku"`
+	Size       string    `json:"size,omitempty"`
+	Colour     string    `json:"colour,omitempty"`
+	PriceDelta int       `json:"price_delta"`
+	Price      int       `json:"price"` // цена мерча с учетом надбавки
+	Stock      *int      `json:"stock"` // nil - неограниченный запас
+	CreatedAt  time.Time `json:"created_at"`
+}
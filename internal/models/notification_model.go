@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Типы уведомлений
+const (
+	NotificationRestock   = "restock"
+	NotificationPriceDrop = "price_drop"
+)
+
+type Notification struct {
+	ID        int        `json:"id"`
+	Kind      string     `json:"kind"`
+	MerchID   *int       `json:"merch_id,omitempty"`
+	Message   string     `json:"message"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Статусы заказа
+const (
+	OrderPlaced         = "placed"
+	OrderPacked         = "packed"
+	OrderReadyForPickup = "ready_for_pickup"
+	OrderDelivered      = "delivered"
+	OrderCancelled      = "cancelled"
+)
+
+// orderTransitions - в какие статусы можно перевести заказ из текущего.
+// Отменить можно только заказ, который еще не выдан.
+var orderTransitions = map[string][]string{
+	OrderPlaced:         {OrderPacked, OrderCancelled},
+	OrderPacked:         {OrderReadyForPickup, OrderCancelled},
+	OrderReadyForPickup: {OrderDelivered, OrderCancelled},
+}
+
+// CanTransitionOrder сообщает, можно ли перевести заказ из статуса from в статус to.
+func CanTransitionOrder(from, to string) bool {
+	for _, s := range orderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOrderStatus сообщает, известен ли статус заказа.
+func IsOrderStatus(status string) bool {
+	switch status {
+	case OrderPlaced, OrderPacked, OrderReadyForPickup, OrderDelivered, OrderCancelled:
+		return true
+	}
+	return false
+}
+
+type Order struct {
+	ID         int       `json:"id"`
+	UserID     string    `json:"user_id"`
+	PaidBy     string    `json:"paid_by"` // отличается от UserID, если заказ - подарок
+	PurchaseID int       `json:"purchase_id"`
+	MerchID    int       `json:"merch_id"`
+	Name       string    `json:"name"`
+	VariantID  *int      `json:"variant_id,omitempty"`
+	SKU        string    `json:"sku,omitempty"`
+	Quantity   int       `json:"quantity"`
+	UnitPrice  int       `json:"unit_price"`
+	Office     string    `json:"office,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
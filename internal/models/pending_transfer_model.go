@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Статусы отложенного перевода
+const (
+	PendingTransferPending   = "pending"
+	PendingTransferAccepted  = "accepted"
+	PendingTransferDeclined  = "declined"
+	PendingTransferCancelled = "cancelled"
+	PendingTransferExpired   = "expired"
+)
+
+type PendingTransfer struct {
+	ID         int        `json:"id"`
+	FromUserID string     `json:"from_user_id"`
+	FromUser   string     `json:"from_user"`
+	ToUserID   string     `json:"to_user_id"`
+	ToUser     string     `json:"to_user"`
+	Amount     int        `json:"amount"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
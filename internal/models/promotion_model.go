@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Типы скидок
+const (
+	DiscountPercent = "percent" // процент от цены
+	DiscountFixed   = "fixed"   // фиксированное число монет
+)
+
+// Promotion - акция на конкретный мерч (MerchID), на категорию (Category)
+// или на весь магазин, если не задано ни то, ни другое.
+type Promotion struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	MerchID       *int      `json:"merch_id,omitempty"`
+	Category      string    `json:"category,omitempty"` // slug категории
+	DiscountType  string    `json:"discount_type"`
+	DiscountValue int       `json:"discount_value"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PromoCode - промокод, который вводят при покупке.
+// MaxUses = 1 - одноразовый, nil - без ограничений.
+type PromoCode struct {
+	ID            int        `json:"id"`
+	Code          string     `json:"code"`
+	MerchID       *int       `json:"merch_id,omitempty"`
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue int        `json:"discount_value"`
+	MaxUses       *int       `json:"max_uses,omitempty"`
+	UsedCount     int        `json:"used_count"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// DiscountAmount считает скидку в монетах для цены price. Скидка не больше самой цены.
+func DiscountAmount(discountType string, value, price int) int {
+	var discount int
+	switch discountType {
+	case DiscountPercent:
+		discount = price * value / 100
+	case DiscountFixed:
+		discount = value
+	}
+	if discount > price {
+		return price
+	}
+	if discount < 0 {
+		return 0
+	}
+	return discount
+}
+
+// Active сообщает, действует ли промокод в момент now и остались ли у него использования.
+func (pc PromoCode) Active(now time.Time) bool {
+	if pc.StartsAt != nil && now.Before(*pc.StartsAt) {
+		return false
+	}
+	if pc.EndsAt != nil && !now.Before(*pc.EndsAt) {
+		return false
+	}
+	return pc.MaxUses == nil || pc.UsedCount < *pc.MaxUses
+}
@@ -3,8 +3,32 @@ package models
 import "time"
 
 type Purchase struct {
+	ID        int       `json:"id"`
 	UserID    string    `json:"user_id"`  
 	MerchID   int       `json:"merch_id"`
+	VariantID *int      `json:"variant_id,omitempty"`
 	Quantity  int       `json:"quantity"`
+	UnitPrice int       `json:"unit_price"` // цена за единицу с учетом скидки
+	Discount  int       `json:"discount,omitempty"` // скидка за единицу
+	PromotionID *int    `json:"promotion_id,omitempty"`
+	PromoCodeID *int    `json:"promo_code_id,omitempty"`
+	Office    string    `json:"office,omitempty"`   // офис выдачи заказа
+	OrderID   int       `json:"order_id,omitempty"` // заполняется после покупки
 	Purchased time.Time `json:"purchased_at"`
-}
\ No newline at end of file
+}
+
+// PurchaseHistoryItem - одна покупка в истории пользователя.
+type PurchaseHistoryItem struct {
+	ID          int       `json:"id"`
+	MerchID     int       `json:"merch_id"`
+	Name        string    `json:"name"`
+	VariantID   *int      `json:"variant_id,omitempty"`
+	SKU         string    `json:"sku,omitempty"`
+	Quantity    int       `json:"quantity"`
+	Returned    int       `json:"returned"` // сколько единиц возвращено или отменено
+	UnitPrice   int       `json:"unit_price"`
+	Discount    int       `json:"discount,omitempty"`
+	PurchasedAt time.Time `json:"purchased_at"`
+	OrderID     *int      `json:"order_id,omitempty"`
+	OrderStatus string    `json:"order_status,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// BalanceMismatch - пользователь, у которого баланс не сходится с ledger.
+type BalanceMismatch struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Balance  int    `json:"balance"`  // баланс в users
+	Expected int    `json:"expected"` // стартовый баланс плюс движения в ledger
+}
+
+// UserBalance - строка отчета по балансам.
+type UserBalance struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Balance   int       `json:"balance"`
+	IsAdmin   bool      `json:"is_admin"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PurchaseReportRow - строка отчета по покупкам за период.
+type PurchaseReportRow struct {
+	PurchaseID  int       `json:"purchase_id"`
+	PurchasedAt time.Time `json:"purchased_at"`
+	Username    string    `json:"username"`
+	Merch       string    `json:"merch"`
+	SKU         string    `json:"sku,omitempty"`
+	Quantity    int       `json:"quantity"`
+	Returned    int       `json:"returned"`
+	UnitPrice   int       `json:"unit_price"`
+	Discount    int       `json:"discount"`
+	Office      string    `json:"office,omitempty"`
+	OrderStatus string    `json:"order_status,omitempty"`
+}
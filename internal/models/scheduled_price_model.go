@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ScheduledPrice - запланированная смена цены товара.
+type ScheduledPrice struct {
+	ID          int        `json:"id"`
+	MerchID     int        `json:"merch_id"`
+	Price       int        `json:"price"`
+	EffectiveAt time.Time  `json:"effective_at"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"` // nil - еще не применена
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AppliedPriceChange - результат применения запланированной цены.
+type AppliedPriceChange struct {
+	MerchID  int
+	Name     string
+	OldPrice int
+	NewPrice int
+}
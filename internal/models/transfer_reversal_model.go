@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type TransferReversal struct {
+	ID            int       `json:"id"`
+	TransferID    int       `json:"transfer_id"`
+	FromUserID    string    `json:"from_user_id"`
+	ToUserID      string    `json:"to_user_id"`
+	Amount        int       `json:"amount"`
+	ReversedBy    string    `json:"reversed_by"`
+	Reason        string    `json:"reason"`
+	AllowNegative bool      `json:"allow_negative"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -6,6 +6,10 @@ type UserMerch struct {
 	MerchID     int       `json:"merch_id"`
 	Name        string    `json:"name"`
 	Price       int   `json:"price"`
+	VariantID   *int      `json:"variant_id,omitempty"`
+	SKU         string    `json:"sku,omitempty"`
+	Size        string    `json:"size,omitempty"`
+	Colour      string    `json:"colour,omitempty"`
 	Quantity    int       `json:"quantity"`
 	PurchasedAt time.Time `json:"purchased_at"`
-}
\ No newline at end of file
+}
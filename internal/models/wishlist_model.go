@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type WishlistItem struct {
+	MerchID    int       `json:"merch_id"`
+	Name       string    `json:"name"`
+	Price      int       `json:"price"`
+	Stock      *int      `json:"stock"`
+	Progress   int       `json:"progress"`   // сколько процентов цены уже накоплено
+	Missing    int       `json:"missing"`    // сколько монет не хватает
+	Affordable bool      `json:"affordable"` // можно купить прямо сейчас
+	AddedAt    time.Time `json:"added_at"`
+}
@@ -0,0 +1,34 @@
+package repository
+
+import "errors"
+
+// Ошибки слоя данных, по которым хэндлеры выбирают HTTP-статус
+var (
+	ErrInsufficientFunds       = errors.New("insufficient balance")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrPendingTransferNotFound = errors.New("pending transfer not found")
+	ErrPendingTransferClosed   = errors.New("pending transfer is already resolved or expired")
+	ErrTransferNotFound        = errors.New("transfer not found")
+	ErrTransferAlreadyReversed = errors.New("transfer is already reversed")
+	ErrRecipientFundsSpent     = errors.New("recipient has already spent the transferred coins")
+	ErrPurchaseNotFound        = errors.New("purchase not found")
+	ErrReturnWindowExpired     = errors.New("return window has expired")
+	ErrMerchNotFound           = errors.New("merch not found")
+	ErrOutOfStock              = errors.New("out of stock")
+	ErrVariantNotFound         = errors.New("merch variant not found")
+	ErrVariantRequired         = errors.New("merch has variants, sku is required")
+	ErrWishlistItemNotFound    = errors.New("merch is not in the wishlist")
+	ErrNotificationNotFound    = errors.New("notification not found")
+	ErrOrderNotFound           = errors.New("order not found")
+	ErrInvalidOrderTransition  = errors.New("order cannot be moved to this status")
+	ErrPromoCodeNotFound       = errors.New("promo code not found")
+	ErrPromoCodeInvalid        = errors.New("promo code is not valid for this purchase")
+	ErrPromoCodeExhausted      = errors.New("promo code has no uses left")
+	ErrPromotionNotFound       = errors.New("promotion not found")
+	ErrCategoryNotFound        = errors.New("category not found")
+	ErrImageNotFound           = errors.New("merch image not found")
+	ErrPurchaseLimitExceeded   = errors.New("purchase limit exceeded")
+	ErrMerchUnavailable        = errors.New("merch is not available for purchase now")
+	ErrMerchNameTaken          = errors.New("merch with this name already exists")
+	ErrScheduledPriceNotFound  = errors.New("scheduled price change not found")
+)
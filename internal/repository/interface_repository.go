@@ -2,29 +2,100 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"EmployeeMerchStore/internal/models"
 )
 
 type LedgerRepositoryInterface interface {
 	SendMoney(ctx context.Context, fromUser, toUser string, amount int) error
 	GetUserTransactions(ctx context.Context, userID string, limit, offset int) (*[]models.Ledger, error)
+	CreatePendingTransfer(ctx context.Context, fromUser, toUser string, amount int, ttl time.Duration) (int, error)
+	ResolvePendingTransfer(ctx context.Context, id int, userID, status string) error
+	ExpirePendingTransfers(ctx context.Context, limit int) (int, error)
+	GetPendingTransfers(ctx context.Context, userID string) ([]models.PendingTransfer, error)
+	ReverseTransfer(ctx context.Context, transferID int, adminID, reason string, allowNegative bool) (models.TransferReversal, error)
+	GrantCoins(ctx context.Context, userID string, amount int) error
 }
 
 type UserRepositoryInterface interface {
 	GetUserCredentials(ctx context.Context, username string) (string, string, error)
 	GetBalance(ctx context.Context, id string) (int, error)
 	CreateUser(ctx context.Context, id, username, hashPswd string, balance int) error
+	IsAdmin(ctx context.Context, id string) (bool, error)
+	SetAdmin(ctx context.Context, id string, admin bool) error
 }
 
 type PurchasesRepositoryInterface interface {
-	BuyMerch(ctx context.Context, userID string, merchID int, quantity, price int) error
+	BuyMerch(ctx context.Context, purchase *models.Purchase) error
 	GetMerchId(ctx context.Context, name string) (int, int, error)
+	GetReturnableMerch(ctx context.Context, userID, name, sku string) (int, *int, error)
+	GetVariant(ctx context.Context, merchID int, sku string) (models.MerchVariant, error)
+	CountVariants(ctx context.Context, merchID int) (int, error)
 	GetUserMerch(ctx context.Context, userID string) ([]*models.UserMerch, error)
+	ReturnMerch(ctx context.Context, userID string, merchID int, variantID *int, window time.Duration) (int, error)
+	GiftMerch(ctx context.Context, purchase *models.Purchase, buyerID, message string) (int, error)
+	GetReceivedGifts(ctx context.Context, userID string) ([]models.Gift, error)
+	GetPurchaseHistory(ctx context.Context, userID string) ([]models.PurchaseHistoryItem, error)
+	GetActivePromotions(ctx context.Context, merchID int) ([]models.Promotion, error)
+	GetPromoCode(ctx context.Context, code string) (models.PromoCode, error)
 }
 
 type MerchRepositoryInterface interface {
 	GetMerch(ctx context.Context, id int) (models.Merch, error)
-	CreateMerch(ctx context.Context, name string, price int, description string) (int, error)
+	ListMerch(ctx context.Context, filter models.MerchFilter) ([]models.Merch, error)
+	CreateMerch(ctx context.Context, name string, price int, description string, stock *int) (int, error)
 	UpdateMerch(ctx context.Context, id int, name string, price int, description string) error
-	DeleteMerch(ctx context.Context, id int) error
-}
\ No newline at end of file
+	ArchiveMerch(ctx context.Context, id int) error
+	RestoreMerch(ctx context.Context, id int) error
+	Restock(ctx context.Context, id, quantity int) (int, error)
+	SetStock(ctx context.Context, id int, stock *int) error
+	ListVariants(ctx context.Context, merchIDs []int) ([]models.MerchVariant, error)
+	GetVariants(ctx context.Context, merchID int) ([]models.MerchVariant, error)
+	CreateVariant(ctx context.Context, variant models.MerchVariant) (int, error)
+	UpdateVariant(ctx context.Context, variant models.MerchVariant) error
+	RestockVariant(ctx context.Context, id, quantity int) (int, error)
+	SetPurchaseLimit(ctx context.Context, id int, limit *int, period string) error
+	SetAvailability(ctx context.Context, id int, from, until *time.Time) error
+	SchedulePrice(ctx context.Context, change models.ScheduledPrice) (int, error)
+	GetScheduledPrices(ctx context.Context, merchID int) ([]models.ScheduledPrice, error)
+	CancelScheduledPrice(ctx context.Context, merchID, id int) error
+	ApplyDuePrices(ctx context.Context) ([]models.AppliedPriceChange, error)
+	SetCategory(ctx context.Context, id int, category string) error
+	SetTags(ctx context.Context, id int, tags []string) error
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	CreateCategory(ctx context.Context, slug, name string) (int, error)
+	AddImage(ctx context.Context, image models.MerchImage) (int, error)
+	ListImages(ctx context.Context, merchIDs []int) ([]models.MerchImage, error)
+	GetImages(ctx context.Context, merchID int) ([]models.MerchImage, error)
+	DeleteImage(ctx context.Context, merchID, id int) (models.MerchImage, error)
+}
+
+type WishlistRepositoryInterface interface {
+	AddToWishlist(ctx context.Context, userID string, merchID int) error
+	RemoveFromWishlist(ctx context.Context, userID string, merchID int) error
+	GetWishlist(ctx context.Context, userID string) ([]models.WishlistItem, error)
+	NotifyWishlisters(ctx context.Context, merchID int, kind, message string) (int, error)
+	GetNotifications(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error)
+	MarkNotificationRead(ctx context.Context, userID string, id int) error
+}
+type OrderRepositoryInterface interface {
+	GetUserOrders(ctx context.Context, userID string, openOnly bool) ([]models.Order, error)
+	ListOrders(ctx context.Context, status string) ([]models.Order, error)
+	UpdateOrderStatus(ctx context.Context, id int, status string) (models.Order, error)
+}
+
+type PromotionRepositoryInterface interface {
+	ListPromotions(ctx context.Context, activeOnly bool) ([]models.Promotion, error)
+	CreatePromotion(ctx context.Context, promotion models.Promotion) (int, error)
+	EndPromotion(ctx context.Context, id int) error
+	ListPromoCodes(ctx context.Context) ([]models.PromoCode, error)
+	CreatePromoCode(ctx context.Context, code models.PromoCode) (int, error)
+	DisablePromoCode(ctx context.Context, id int) error
+}
+
+type ReportRepositoryInterface interface {
+	ReconcileBalances(ctx context.Context, initialBalance int) ([]models.BalanceMismatch, error)
+	GetBalances(ctx context.Context) ([]models.UserBalance, error)
+	GetPurchases(ctx context.Context, from, to time.Time) ([]models.PurchaseReportRow, error)
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
     "EmployeeMerchStore/internal/models"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -43,18 +46,20 @@ func (lr *LedgerRepository) SendMoney(ctx context.Context, fromUser, toUser stri
 		return fmt.Errorf("balance update failed: %w", err)
 	}
 
-	// Логируем списание у отправителя
-	_, err = tx.Exec(ctx, `
+	// Логируем списание у отправителя, id этой записи - id перевода
+	var transferID int
+	err = tx.QueryRow(ctx, `
 		INSERT INTO "MerchStore".ledger (user_id, reference_id_usr, movement_type, amount) 
-		VALUES ($1, $2, 'transfer_out', $3)`, fromUser, toUser, amount)
+		VALUES ($1, $2, 'transfer_out', $3)
+		RETURNING id`, fromUser, toUser, amount).Scan(&transferID)
 	if err != nil {
 		return fmt.Errorf("failed to log sender transaction: %w", err)
 	}
 
-	// Логируем зачисление у получателя
+	// Логируем зачисление у получателя со ссылкой на перевод
 	_, err = tx.Exec(ctx, `
-		INSERT INTO "MerchStore".ledger (user_id, reference_id_usr, movement_type, amount) 
-		VALUES ($1, $2, 'transfer_in', $3)`, toUser, fromUser, amount)
+		INSERT INTO "MerchStore".ledger (user_id, reference_id, reference_id_usr, movement_type, amount) 
+		VALUES ($1, $2, $3, 'transfer_in', $4)`, toUser, transferID, fromUser, amount)
 	if err != nil {
 		return fmt.Errorf("failed to log recipient transaction: %w", err)
 	}
@@ -68,10 +73,361 @@ func (lr *LedgerRepository) SendMoney(ctx context.Context, fromUser, toUser stri
 	return nil
 }
 
+// GrantCoins начисляет пользователю монеты с записью в ledger.
+func (lr *LedgerRepository) GrantCoins(ctx context.Context, userID string, amount int) error {
+	tx, err := lr.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("transaction start failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ct, err := tx.Exec(ctx, `UPDATE "MerchStore".users SET balance = balance + $2 WHERE id = $1`, userID, amount)
+	if err != nil {
+		return fmt.Errorf("balance update failed: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO "MerchStore".ledger (user_id, movement_type, amount)
+		VALUES ($1, $2, $3)`, userID, models.MovementGrant, amount)
+	if err != nil {
+		return fmt.Errorf("failed to log grant: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreatePendingTransfer списывает монеты отправителя и замораживает их до решения получателя.
+// Если получатель не ответит за ttl, перевод вернется отправителю через ExpirePendingTransfers.
+func (lr *LedgerRepository) CreatePendingTransfer(ctx context.Context, fromUser, toUser string, amount int, ttl time.Duration) (int, error) {
+	tx, err := lr.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("transaction start failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ct, err := tx.Exec(ctx, `
+		UPDATE "MerchStore".users
+		SET balance = balance - $2
+		WHERE id = $1 AND balance >= $2`, fromUser, amount)
+	if err != nil {
+		return 0, fmt.Errorf("balance update failed: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return 0, ErrInsufficientFunds
+	}
+
+	var transferID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO "MerchStore".pending_transfers (from_user_id, to_user_id, amount, expires_at)
+		VALUES ($1, $2, $3, now() + make_interval(secs => $4))
+		RETURNING id`, fromUser, toUser, amount, ttl.Seconds()).Scan(&transferID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create pending transfer: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO "MerchStore".ledger (user_id, reference_id, reference_id_usr, movement_type, amount)
+		VALUES ($1, $2, $3, $4, $5)`, fromUser, transferID, toUser, models.MovementEscrowHold, amount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to log escrow hold: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return transferID, nil
+}
+
+// ResolvePendingTransfer переводит отложенный перевод в итоговый статус.
+// Принять или отклонить перевод может только получатель, отменить - только отправитель.
+func (lr *LedgerRepository) ResolvePendingTransfer(ctx context.Context, id int, userID, status string) error {
+	tx, err := lr.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("transaction start failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var pt models.PendingTransfer
+	var expired bool
+	err = tx.QueryRow(ctx, `
+		SELECT id, from_user_id, to_user_id, amount, status, expires_at <= now()
+		FROM "MerchStore".pending_transfers
+		WHERE id = $1
+		FOR UPDATE`, id).Scan(&pt.ID, &pt.FromUserID, &pt.ToUserID, &pt.Amount, &pt.Status, &expired)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPendingTransferNotFound
+		}
+		return fmt.Errorf("failed to get pending transfer: %w", err)
+	}
+
+	// Чужие переводы не раскрываем
+	switch status {
+	case models.PendingTransferAccepted, models.PendingTransferDeclined:
+		if pt.ToUserID != userID {
+			return ErrPendingTransferNotFound
+		}
+	case models.PendingTransferCancelled:
+		if pt.FromUserID != userID {
+			return ErrPendingTransferNotFound
+		}
+	default:
+		return fmt.Errorf("unsupported pending transfer status %q", status)
+	}
+
+	if pt.Status != models.PendingTransferPending || expired {
+		return ErrPendingTransferClosed
+	}
+
+	if status == models.PendingTransferAccepted {
+		_, err = tx.Exec(ctx, `UPDATE "MerchStore".users SET balance = balance + $2 WHERE id = $1`, pt.ToUserID, pt.Amount)
+		if err != nil {
+			return fmt.Errorf("balance update failed: %w", err)
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO "MerchStore".ledger (user_id, reference_id, reference_id_usr, movement_type, amount)
+			VALUES ($1, $2, $3, $4, $5)`, pt.ToUserID, pt.ID, pt.FromUserID, models.MovementEscrowIn, pt.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to log recipient transaction: %w", err)
+		}
+		if err := setPendingTransferStatus(ctx, tx, pt.ID, status); err != nil {
+			return err
+		}
+	} else if err := releasePendingTransfer(ctx, tx, &pt, status); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// ExpirePendingTransfers возвращает отправителям монеты по переводам, которые не приняли вовремя.
+// Обрабатывает не больше limit переводов за вызов и возвращает их количество.
+func (lr *LedgerRepository) ExpirePendingTransfers(ctx context.Context, limit int) (int, error) {
+	tx, err := lr.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("transaction start failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED, чтобы не ждать переводы, которые прямо сейчас принимают
+	rows, err := tx.Query(ctx, `
+		SELECT id, from_user_id, to_user_id, amount
+		FROM "MerchStore".pending_transfers
+		WHERE status = 'pending' AND expires_at <= now()
+		ORDER BY expires_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch expired transfers: %w", err)
+	}
+
+	var expired []models.PendingTransfer
+	for rows.Next() {
+		var pt models.PendingTransfer
+		if err := rows.Scan(&pt.ID, &pt.FromUserID, &pt.ToUserID, &pt.Amount); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan expired transfer: %w", err)
+		}
+		expired = append(expired, pt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	for i := range expired {
+		if err := releasePendingTransfer(ctx, tx, &expired[i], models.PendingTransferExpired); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return len(expired), nil
+}
+
+// GetPendingTransfers возвращает ожидающие переводы, где пользователь отправитель или получатель.
+func (lr *LedgerRepository) GetPendingTransfers(ctx context.Context, userID string) ([]models.PendingTransfer, error) {
+	query := `
+		SELECT pt.id, pt.from_user_id, uf.username, pt.to_user_id, ut.username,
+		       pt.amount, pt.status, pt.created_at, pt.expires_at, pt.resolved_at
+		FROM "MerchStore".pending_transfers pt
+		JOIN "MerchStore".users uf ON uf.id = pt.from_user_id
+		JOIN "MerchStore".users ut ON ut.id = pt.to_user_id
+		WHERE (pt.from_user_id = $1 OR pt.to_user_id = $1) AND pt.status = 'pending'
+		ORDER BY pt.created_at DESC
+	`
+
+	rows, err := lr.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pending transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []models.PendingTransfer
+	for rows.Next() {
+		var pt models.PendingTransfer
+		err := rows.Scan(&pt.ID, &pt.FromUserID, &pt.FromUser, &pt.ToUserID, &pt.ToUser,
+			&pt.Amount, &pt.Status, &pt.CreatedAt, &pt.ExpiresAt, &pt.ResolvedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pending transfer: %w", err)
+		}
+		transfers = append(transfers, pt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// ReverseTransfer откатывает прямой перевод (id записи 'transfer_out' в ledger) компенсирующими записями.
+// Если получатель уже потратил монеты, перевод откатывается только при allowNegative,
+// и тогда баланс получателя уходит в минус.
+func (lr *LedgerRepository) ReverseTransfer(ctx context.Context, transferID int, adminID, reason string, allowNegative bool) (models.TransferReversal, error) {
+	rev := models.TransferReversal{
+		TransferID:    transferID,
+		ReversedBy:    adminID,
+		Reason:        reason,
+		AllowNegative: allowNegative,
+	}
+
+	tx, err := lr.db.Begin(ctx)
+	if err != nil {
+		return rev, fmt.Errorf("transaction start failed: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		SELECT user_id, reference_id_usr, amount::integer
+		FROM "MerchStore".ledger
+		WHERE id = $1 AND movement_type = 'transfer_out'`, transferID).Scan(&rev.FromUserID, &rev.ToUserID, &rev.Amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rev, ErrTransferNotFound
+		}
+		return rev, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	// Блокируем обоих пользователей в одном порядке, чтобы не словить дедлок
+	rows, err := tx.Query(ctx, `
+		SELECT id, balance FROM "MerchStore".users
+		WHERE id IN ($1, $2)
+		ORDER BY id
+		FOR UPDATE`, rev.FromUserID, rev.ToUserID)
+	if err != nil {
+		return rev, fmt.Errorf("failed to lock users: %w", err)
+	}
+	var recipientBalance int
+	for rows.Next() {
+		var id string
+		var balance int
+		if err := rows.Scan(&id, &balance); err != nil {
+			rows.Close()
+			return rev, fmt.Errorf("failed to scan user balance: %w", err)
+		}
+		if id == rev.ToUserID {
+			recipientBalance = balance
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return rev, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	var reversed bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM "MerchStore".transfer_reversals WHERE transfer_id = $1)`, transferID).Scan(&reversed)
+	if err != nil {
+		return rev, fmt.Errorf("failed to check reversal: %w", err)
+	}
+	if reversed {
+		return rev, ErrTransferAlreadyReversed
+	}
+
+	if recipientBalance < rev.Amount && !allowNegative {
+		return rev, ErrRecipientFundsSpent
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO "MerchStore".transfer_reversals
+			(transfer_id, from_user_id, to_user_id, amount, reversed_by, reason, allow_negative)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		transferID, rev.FromUserID, rev.ToUserID, rev.Amount, adminID, reason, allowNegative,
+	).Scan(&rev.ID, &rev.CreatedAt)
+	if err != nil {
+		return rev, fmt.Errorf("failed to record reversal: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE "MerchStore".users 
+		SET balance = balance + 
+			CASE 
+				WHEN id = $1 THEN $3::integer
+				WHEN id = $2 THEN -$3::integer
+			END 
+		WHERE id IN ($1, $2)`, rev.FromUserID, rev.ToUserID, rev.Amount)
+	if err != nil {
+		return rev, fmt.Errorf("balance update failed: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO "MerchStore".ledger (user_id, reference_id, reference_id_usr, movement_type, amount)
+		VALUES ($1, $3, $2, $4, $5), ($2, $3, $1, $6, $5)`,
+		rev.ToUserID, rev.FromUserID, transferID, models.MovementReversalOut, rev.Amount, models.MovementReversalIn)
+	if err != nil {
+		return rev, fmt.Errorf("failed to log reversal: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return rev, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return rev, nil
+}
+
+// releasePendingTransfer возвращает замороженные монеты отправителю в рамках транзакции tx.
+func releasePendingTransfer(ctx context.Context, tx pgx.Tx, pt *models.PendingTransfer, status string) error {
+	_, err := tx.Exec(ctx, `UPDATE "MerchStore".users SET balance = balance + $2 WHERE id = $1`, pt.FromUserID, pt.Amount)
+	if err != nil {
+		return fmt.Errorf("balance update failed: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO "MerchStore".ledger (user_id, reference_id, reference_id_usr, movement_type, amount)
+		VALUES ($1, $2, $3, $4, $5)`, pt.FromUserID, pt.ID, pt.ToUserID, models.MovementEscrowRelease, pt.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to log escrow release: %w", err)
+	}
+
+	return setPendingTransferStatus(ctx, tx, pt.ID, status)
+}
+
+func setPendingTransferStatus(ctx context.Context, tx pgx.Tx, id int, status string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE "MerchStore".pending_transfers
+		SET status = $2, resolved_at = now()
+		WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update pending transfer status: %w", err)
+	}
+	return nil
+}
 
 func (lr *LedgerRepository) GetUserTransactions(ctx context.Context, userID string, limit, offset int) (*[]models.Ledger, error) {
 	query := `
-		SELECT id, user_id, movement_type, amount, reference_id, reference_id_usr, created_at
+		SELECT id, user_id, movement_type, amount, reference_id, reference_id_usr, discount, created_at
 		FROM "MerchStore".ledger
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -87,7 +443,7 @@ func (lr *LedgerRepository) GetUserTransactions(ctx context.Context, userID stri
 	var transactions []models.Ledger
 	for rows.Next() {
 		var entry models.Ledger
-		err := rows.Scan(&entry.ID, &entry.UserID, &entry.MovementType, &entry.Amount, &entry.ReferenceID, &entry.Reference_id_usr, &entry.CreatedAt)
+		err := rows.Scan(&entry.ID, &entry.UserID, &entry.MovementType, &entry.Amount, &entry.ReferenceID, &entry.Reference_id_usr, &entry.Discount, &entry.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
     "EmployeeMerchStore/internal/models"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -16,47 +20,545 @@ func NewMerchRepository(db *pgxpool.Pool) *MerchRepository {
 	return &MerchRepository{db: db}
 }
 
+const merchColumns = `
+	m.id, m.name, m.price, m.description, m.stock, m.purchase_limit, m.limit_period,
+	m.available_from, m.available_until, m.archived_at, COALESCE(c.slug, ''),
+	ARRAY(SELECT t.tag FROM "MerchStore".merch_tags t WHERE t.merch_id = m.id ORDER BY t.tag), m.created_at`
+
+const merchJoins = `
+	FROM "MerchStore".merch m
+	LEFT JOIN "MerchStore".categories c ON c.id = m.category_id`
+
+func scanMerch(row pgx.Row, merch *models.Merch) error {
+	return row.Scan(&merch.ID, &merch.Name, &merch.Price, &merch.Description, &merch.Stock,
+		&merch.PurchaseLimit, &merch.LimitPeriod, &merch.AvailableFrom, &merch.AvailableUntil, &merch.ArchivedAt, &merch.Category, &merch.Tags, &merch.CreatedAt)
+}
+
 func (mr *MerchRepository) GetMerch(ctx context.Context, id int) (models.Merch, error) {
-	query := `SELECT id, name, price, description, created_at FROM "MerchStore".merch WHERE id = $1`
+	query := `SELECT ` + merchColumns + merchJoins + ` WHERE m.id = $1`
 	var merch models.Merch
-	if err := mr.db.QueryRow(ctx, query, id).Scan(&merch.ID, &merch.Name, &merch.Price, &merch.Description, &merch.CreatedAt); err != nil {
+	if err := scanMerch(mr.db.QueryRow(ctx, query, id), &merch); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Merch{}, fmt.Errorf("GetMerch: %w", ErrMerchNotFound)
+		}
 		return models.Merch{}, fmt.Errorf("GetMerch: %w", err)
 	}
 	return merch, nil
 }
 
-func (mr *MerchRepository) CreateMerch(ctx context.Context, name string, price int, description string) (int, error) {
-	query := `INSERT INTO "MerchStore".merch (name, price, description) VALUES ($1, $2, $3) RETURNING id`
+// ListMerch возвращает каталог, отфильтрованный по категории и тегу.
+func (mr *MerchRepository) ListMerch(ctx context.Context, filter models.MerchFilter) ([]models.Merch, error) {
+	query := `SELECT ` + merchColumns + merchJoins + `
+		WHERE ($1 = '' OR c.slug = $1)
+		  AND ($2 = '' OR EXISTS (SELECT 1 FROM "MerchStore".merch_tags t WHERE t.merch_id = m.id AND t.tag = $2))
+		  AND (NOT $3 OR ((m.available_from IS NULL OR m.available_from <= now())
+		                  AND (m.available_until IS NULL OR m.available_until > now())))
+		  AND (m.archived_at IS NOT NULL) = $4
+		ORDER BY m.name`
+
+	rows, err := mr.db.Query(ctx, query, filter.Category, filter.Tag, filter.AvailableOnly, filter.Archived)
+	if err != nil {
+		return nil, fmt.Errorf("ListMerch: %w", err)
+	}
+	defer rows.Close()
+
+	var merchList []models.Merch
+	for rows.Next() {
+		var merch models.Merch
+		if err := scanMerch(rows, &merch); err != nil {
+			return nil, fmt.Errorf("ListMerch scan: %w", err)
+		}
+		merchList = append(merchList, merch)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("ListMerch rows error: %w", rows.Err())
+	}
+
+	return merchList, nil
+}
+
+func (mr *MerchRepository) CreateMerch(ctx context.Context, name string, price int, description string, stock *int) (int, error) {
+	query := `INSERT INTO "MerchStore".merch (name, price, description, stock) VALUES ($1, $2, $3, $4) RETURNING id`
 
     var merchID int 
-    err := mr.db.QueryRow(ctx, query, name, price, description).Scan(&merchID)
+    err := mr.db.QueryRow(ctx, query, name, price, description, stock).Scan(&merchID)
     if err != nil {
+        if isUniqueViolation(err) {
+            return 0, fmt.Errorf("CreateMerch: %w", ErrMerchNameTaken)
+        }
         return 0, fmt.Errorf("CreateMerch: %w", err)
     }
 
     return merchID, nil
 }
 
-func (mr *MerchRepository) UpdateMerch(ctx context.Context, id, name string, price int, description string) error {
+func (mr *MerchRepository) UpdateMerch(ctx context.Context, id int, name string, price int, description string) error {
 	query := `UPDATE "MerchStore".merch SET name = $1, price = $2, description = $3 WHERE id = $4`
 	ct, err := mr.db.Exec(ctx, query, name, price, description, id)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("UpdateMerch: %w", ErrMerchNameTaken)
+		}
 		return fmt.Errorf("UpdateMerch: %w", err)
 	}
 	if ct.RowsAffected() == 0 {
-		return fmt.Errorf("UpdateMerch: no merch found with id %s", id)
+		return fmt.Errorf("UpdateMerch: %w", ErrMerchNotFound)
+	}
+	return nil
+}
+
+// ArchiveMerch снимает товар с продажи. Строка остается, чтобы инвентари, заказы
+// и история операций продолжали показывать товар под его именем.
+func (mr *MerchRepository) ArchiveMerch(ctx context.Context, id int) error {
+	query := `UPDATE "MerchStore".merch SET archived_at = now() WHERE id = $1 AND archived_at IS NULL`
+	ct, err := mr.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("ArchiveMerch: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("ArchiveMerch: %w", ErrMerchNotFound)
 	}
 	return nil
 }
 
-func (mr *MerchRepository) DeleteMerch(ctx context.Context, id string) error {
-	query := `DELETE FROM "MerchStore".merch WHERE id = $1`
+// RestoreMerch возвращает архивный товар в продажу, если его имя еще не занято активным товаром.
+func (mr *MerchRepository) RestoreMerch(ctx context.Context, id int) error {
+	query := `UPDATE "MerchStore".merch SET archived_at = NULL WHERE id = $1 AND archived_at IS NOT NULL`
 	ct, err := mr.db.Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("DeleteMerch: %w", err)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("RestoreMerch: %w", ErrMerchNameTaken)
+		}
+		return fmt.Errorf("RestoreMerch: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("RestoreMerch: %w", ErrMerchNotFound)
+	}
+	return nil
+}
+
+// Restock добавляет quantity единиц на склад и возвращает новый остаток.
+// Товар с неограниченным запасом после пополнения становится ограниченным.
+func (mr *MerchRepository) Restock(ctx context.Context, id, quantity int) (int, error) {
+	query := `UPDATE "MerchStore".merch SET stock = COALESCE(stock, 0) + $1 WHERE id = $2 RETURNING stock`
+
+	var stock int
+	if err := mr.db.QueryRow(ctx, query, quantity, id).Scan(&stock); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("Restock: %w", ErrMerchNotFound)
+		}
+		return 0, fmt.Errorf("Restock: %w", err)
+	}
+	return stock, nil
+}
+
+// SetStock выставляет остаток на складе, nil снимает ограничение.
+func (mr *MerchRepository) SetStock(ctx context.Context, id int, stock *int) error {
+	query := `UPDATE "MerchStore".merch SET stock = $1 WHERE id = $2`
+	ct, err := mr.db.Exec(ctx, query, stock, id)
+	if err != nil {
+		return fmt.Errorf("SetStock: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("SetStock: %w", ErrMerchNotFound)
+	}
+	return nil
+}
+
+// SetPurchaseLimit задает лимит покупок товара на сотрудника за период. nil снимает лимит.
+func (mr *MerchRepository) SetPurchaseLimit(ctx context.Context, id int, limit *int, period string) error {
+	query := `UPDATE "MerchStore".merch SET purchase_limit = $1, limit_period = $2 WHERE id = $3`
+	ct, err := mr.db.Exec(ctx, query, limit, period, id)
+	if err != nil {
+		return fmt.Errorf("SetPurchaseLimit: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("SetPurchaseLimit: %w", ErrMerchNotFound)
+	}
+	return nil
+}
+
+// SetAvailability задает окно, в которое товар виден в каталоге и продается. nil - без ограничения.
+func (mr *MerchRepository) SetAvailability(ctx context.Context, id int, from, until *time.Time) error {
+	query := `UPDATE "MerchStore".merch SET available_from = $1, available_until = $2 WHERE id = $3`
+	ct, err := mr.db.Exec(ctx, query, from, until, id)
+	if err != nil {
+		return fmt.Errorf("SetAvailability: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("SetAvailability: %w", ErrMerchNotFound)
+	}
+	return nil
+}
+
+func (mr *MerchRepository) SchedulePrice(ctx context.Context, change models.ScheduledPrice) (int, error) {
+	query := `
+		INSERT INTO "MerchStore".scheduled_prices (merch_id, price, effective_at)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	var id int
+	if err := mr.db.QueryRow(ctx, query, change.MerchID, change.Price, change.EffectiveAt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("SchedulePrice: %w", err)
+	}
+	return id, nil
+}
+
+// GetScheduledPrices возвращает запланированные и уже примененные смены цены товара.
+func (mr *MerchRepository) GetScheduledPrices(ctx context.Context, merchID int) ([]models.ScheduledPrice, error) {
+	query := `
+		SELECT id, merch_id, price, effective_at, applied_at, created_at
+		FROM "MerchStore".scheduled_prices
+		WHERE merch_id = $1
+		ORDER BY effective_at`
+
+	rows, err := mr.db.Query(ctx, query, merchID)
+	if err != nil {
+		return nil, fmt.Errorf("GetScheduledPrices: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.ScheduledPrice
+	for rows.Next() {
+		var c models.ScheduledPrice
+		if err := rows.Scan(&c.ID, &c.MerchID, &c.Price, &c.EffectiveAt, &c.AppliedAt, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("GetScheduledPrices scan: %w", err)
+		}
+		changes = append(changes, c)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("GetScheduledPrices rows error: %w", rows.Err())
+	}
+
+	return changes, nil
+}
+
+// CancelScheduledPrice удаляет смену цены, которая еще не применена.
+func (mr *MerchRepository) CancelScheduledPrice(ctx context.Context, merchID, id int) error {
+	query := `DELETE FROM "MerchStore".scheduled_prices WHERE id = $1 AND merch_id = $2 AND applied_at IS NULL`
+	ct, err := mr.db.Exec(ctx, query, id, merchID)
+	if err != nil {
+		return fmt.Errorf("CancelScheduledPrice: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("CancelScheduledPrice: %w", ErrScheduledPriceNotFound)
+	}
+	return nil
+}
+
+// ApplyDuePrices применяет наступившие смены цены. Если у товара их накопилось несколько,
+// ставится самая поздняя, а остальные помечаются примененными вместе с ней.
+func (mr *MerchRepository) ApplyDuePrices(ctx context.Context) ([]models.AppliedPriceChange, error) {
+	tx, err := mr.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		WITH due AS (
+			SELECT id, merch_id, price, effective_at
+			FROM "MerchStore".scheduled_prices
+			WHERE applied_at IS NULL AND effective_at <= now()
+			FOR UPDATE SKIP LOCKED
+		), latest AS (
+			SELECT DISTINCT ON (merch_id) merch_id, price
+			FROM due
+			ORDER BY merch_id, effective_at DESC, id DESC
+		), marked AS (
+			UPDATE "MerchStore".scheduled_prices s
+			SET applied_at = now()
+			FROM due
+			WHERE s.id = due.id
+		), old AS (
+			SELECT m.id, m.price FROM "MerchStore".merch m JOIN latest l ON l.merch_id = m.id FOR UPDATE OF m
+		)
+		UPDATE "MerchStore".merch m
+		SET price = l.price
+		FROM latest l, old o
+		WHERE m.id = l.merch_id AND o.id = m.id
+		RETURNING m.id, m.name, o.price, m.price`)
+	if err != nil {
+		return nil, fmt.Errorf("ApplyDuePrices: %w", err)
+	}
+
+	var applied []models.AppliedPriceChange
+	for rows.Next() {
+		var a models.AppliedPriceChange
+		if err := rows.Scan(&a.MerchID, &a.Name, &a.OldPrice, &a.NewPrice); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("ApplyDuePrices scan: %w", err)
+		}
+		applied = append(applied, a)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("ApplyDuePrices rows error: %w", rows.Err())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return applied, nil
+}
+
+// SetCategory переносит товар в категорию по slug. Пустой slug убирает категорию.
+func (mr *MerchRepository) SetCategory(ctx context.Context, id int, category string) error {
+	var categoryID *int
+	if category != "" {
+		var cid int
+		err := mr.db.QueryRow(ctx, `SELECT id FROM "MerchStore".categories WHERE slug = $1`, category).Scan(&cid)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("SetCategory: %w", ErrCategoryNotFound)
+			}
+			return fmt.Errorf("SetCategory: %w", err)
+		}
+		categoryID = &cid
+	}
+
+	ct, err := mr.db.Exec(ctx, `UPDATE "MerchStore".merch SET category_id = $1 WHERE id = $2`, categoryID, id)
+	if err != nil {
+		return fmt.Errorf("SetCategory: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("SetCategory: %w", ErrMerchNotFound)
+	}
+	return nil
+}
+
+// SetTags заменяет все теги товара на tags.
+func (mr *MerchRepository) SetTags(ctx context.Context, id int, tags []string) error {
+	tx, err := mr.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем товар, чтобы параллельные замены тегов не перемешались
+	err = tx.QueryRow(ctx, `SELECT id FROM "MerchStore".merch WHERE id = $1 FOR UPDATE`, id).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("SetTags: %w", ErrMerchNotFound)
+		}
+		return fmt.Errorf("SetTags: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM "MerchStore".merch_tags WHERE merch_id = $1`, id); err != nil {
+		return fmt.Errorf("SetTags: failed to delete tags: %w", err)
+	}
+	if len(tags) > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO "MerchStore".merch_tags (merch_id, tag)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT DO NOTHING`, id, tags)
+		if err != nil {
+			return fmt.Errorf("SetTags: failed to insert tags: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (mr *MerchRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	rows, err := mr.db.Query(ctx, `SELECT id, slug, name FROM "MerchStore".categories ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("ListCategories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.ID, &c.Slug, &c.Name); err != nil {
+			return nil, fmt.Errorf("ListCategories scan: %w", err)
+		}
+		categories = append(categories, c)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("ListCategories rows error: %w", rows.Err())
+	}
+
+	return categories, nil
+}
+
+func (mr *MerchRepository) CreateCategory(ctx context.Context, slug, name string) (int, error) {
+	var id int
+	err := mr.db.QueryRow(ctx, `INSERT INTO "MerchStore".categories (slug, name) VALUES ($1, $2) RETURNING id`, slug, name).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("CreateCategory: %w", err)
+	}
+	return id, nil
+}
+
+const variantColumns = `
+	v.id, v.merch_id, v.sku, COALESCE(v.size, ''), COALESCE(v.colour, ''),
+	v.price_delta, m.price + v.price_delta, v.stock, v.created_at`
+
+// ListVariants возвращает варианты товаров merchIDs, сгруппированные по merch_id.
+func (mr *MerchRepository) ListVariants(ctx context.Context, merchIDs []int) ([]models.MerchVariant, error) {
+	query := `SELECT ` + variantColumns + `
+		FROM "MerchStore".merch_variants v
+		JOIN "MerchStore".merch m ON m.id = v.merch_id
+		WHERE v.merch_id = ANY($1)
+		ORDER BY v.merch_id, v.id`
+	return mr.queryVariants(ctx, "ListVariants", query, merchIDs)
+}
+
+func (mr *MerchRepository) GetVariants(ctx context.Context, merchID int) ([]models.MerchVariant, error) {
+	query := `SELECT ` + variantColumns + `
+		FROM "MerchStore".merch_variants v
+		JOIN "MerchStore".merch m ON m.id = v.merch_id
+		WHERE v.merch_id = $1
+		ORDER BY v.id`
+	return mr.queryVariants(ctx, "GetVariants", query, merchID)
+}
+
+func (mr *MerchRepository) queryVariants(ctx context.Context, op, query string, args ...interface{}) ([]models.MerchVariant, error) {
+	rows, err := mr.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var variants []models.MerchVariant
+	for rows.Next() {
+		var v models.MerchVariant
+		if err := rows.Scan(&v.ID, &v.MerchID, &v.SKU, &v.Size, &v.Colour, &v.PriceDelta, &v.Price, &v.Stock, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		variants = append(variants, v)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("%s rows error: %w", op, rows.Err())
+	}
+
+	return variants, nil
+}
+
+func (mr *MerchRepository) CreateVariant(ctx context.Context, variant models.MerchVariant) (int, error) {
+	query := `
+		INSERT INTO "MerchStore".merch_variants (merch_id, sku, size, colour, price_delta, stock)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6)
+		RETURNING id`
+
+	var id int
+	err := mr.db.QueryRow(ctx, query, variant.MerchID, variant.SKU, variant.Size, variant.Colour, variant.PriceDelta, variant.Stock).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("CreateVariant: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateVariant обновляет SKU, размер, цвет и надбавку. Остаток меняется через RestockVariant.
+func (mr *MerchRepository) UpdateVariant(ctx context.Context, variant models.MerchVariant) error {
+	query := `
+		UPDATE "MerchStore".merch_variants
+		SET sku = $1, size = NULLIF($2, ''), colour = NULLIF($3, ''), price_delta = $4
+		WHERE id = $5 AND merch_id = $6`
+	ct, err := mr.db.Exec(ctx, query, variant.SKU, variant.Size, variant.Colour, variant.PriceDelta, variant.ID, variant.MerchID)
+	if err != nil {
+		return fmt.Errorf("UpdateVariant: %w", err)
 	}
 	if ct.RowsAffected() == 0 {
-		return fmt.Errorf("DeleteMerch: no merch found with id %s", id)
+		return fmt.Errorf("UpdateVariant: %w", ErrVariantNotFound)
 	}
 	return nil
 }
+
+// RestockVariant добавляет quantity единиц варианта на склад и возвращает новый остаток.
+func (mr *MerchRepository) RestockVariant(ctx context.Context, id, quantity int) (int, error) {
+	query := `UPDATE "MerchStore".merch_variants SET stock = COALESCE(stock, 0) + $1 WHERE id = $2 RETURNING stock`
+
+	var stock int
+	if err := mr.db.QueryRow(ctx, query, quantity, id).Scan(&stock); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("RestockVariant: %w", ErrVariantNotFound)
+		}
+		return 0, fmt.Errorf("RestockVariant: %w", err)
+	}
+	return stock, nil
+}
+
+const imageColumns = `id, merch_id, key, thumbnail_key, url, thumbnail_url, content_type, width, height, created_at`
+
+func scanImage(row pgx.Row, img *models.MerchImage) error {
+	return row.Scan(&img.ID, &img.MerchID, &img.Key, &img.ThumbnailKey, &img.URL, &img.ThumbnailURL,
+		&img.ContentType, &img.Width, &img.Height, &img.CreatedAt)
+}
+
+func (mr *MerchRepository) AddImage(ctx context.Context, img models.MerchImage) (int, error) {
+	query := `
+		INSERT INTO "MerchStore".merch_images (merch_id, key, thumbnail_key, url, thumbnail_url, content_type, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+
+	var id int
+	err := mr.db.QueryRow(ctx, query, img.MerchID, img.Key, img.ThumbnailKey, img.URL, img.ThumbnailURL,
+		img.ContentType, img.Width, img.Height).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("AddImage: %w", err)
+	}
+	return id, nil
+}
+
+// ListImages возвращает картинки товаров merchIDs в порядке загрузки.
+func (mr *MerchRepository) ListImages(ctx context.Context, merchIDs []int) ([]models.MerchImage, error) {
+	query := `SELECT ` + imageColumns + ` FROM "MerchStore".merch_images WHERE merch_id = ANY($1) ORDER BY merch_id, id`
+	return mr.queryImages(ctx, "ListImages", query, merchIDs)
+}
+
+func (mr *MerchRepository) GetImages(ctx context.Context, merchID int) ([]models.MerchImage, error) {
+	query := `SELECT ` + imageColumns + ` FROM "MerchStore".merch_images WHERE merch_id = $1 ORDER BY id`
+	return mr.queryImages(ctx, "GetImages", query, merchID)
+}
+
+func (mr *MerchRepository) queryImages(ctx context.Context, op, query string, args ...interface{}) ([]models.MerchImage, error) {
+	rows, err := mr.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var images []models.MerchImage
+	for rows.Next() {
+		var img models.MerchImage
+		if err := scanImage(rows, &img); err != nil {
+			return nil, fmt.Errorf("%s scan: %w", op, err)
+		}
+		images = append(images, img)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("%s rows error: %w", op, rows.Err())
+	}
+
+	return images, nil
+}
+
+// DeleteImage удаляет запись о картинке и возвращает ее, чтобы можно было удалить файлы из хранилища.
+func (mr *MerchRepository) DeleteImage(ctx context.Context, merchID, id int) (models.MerchImage, error) {
+	query := `DELETE FROM "MerchStore".merch_images WHERE id = $1 AND merch_id = $2 RETURNING ` + imageColumns
+
+	var img models.MerchImage
+	if err := scanImage(mr.db.QueryRow(ctx, query, id, merchID), &img); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.MerchImage{}, fmt.Errorf("DeleteImage: %w", ErrImageNotFound)
+		}
+		return models.MerchImage{}, fmt.Errorf("DeleteImage: %w", err)
+	}
+	return img, nil
+}
+
+// isUniqueViolation - нарушено ограничение уникальности (SQLSTATE 23505).
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EmployeeMerchStore/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type OrderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+const orderColumns = `
+	o.id, o.user_id, o.paid_by, o.purchase_id, o.merch_id, m.name, o.variant_id, COALESCE(v.sku, ''),
+	o.quantity, o.unit_price, COALESCE(o.office, ''), o.status, o.created_at, o.updated_at`
+
+const orderJoins = `
+	FROM "MerchStore".orders o
+	JOIN "MerchStore".merch m ON m.id = o.merch_id
+	LEFT JOIN "MerchStore".merch_variants v ON v.id = o.variant_id`
+
+// GetUserOrders возвращает заказы пользователя. С openOnly - только еще не выданные и не отмененные.
+func (or *OrderRepository) GetUserOrders(ctx context.Context, userID string, openOnly bool) ([]models.Order, error) {
+	query := `SELECT ` + orderColumns + orderJoins + `
+		WHERE o.user_id = $1 AND (NOT $2 OR o.status NOT IN ($3, $4))
+		ORDER BY o.created_at DESC`
+
+	return or.queryOrders(ctx, query, userID, openOnly, models.OrderDelivered, models.OrderCancelled)
+}
+
+// ListOrders возвращает заказы для администратора, самые старые первыми.
+// Пустой status - все заказы.
+func (or *OrderRepository) ListOrders(ctx context.Context, status string) ([]models.Order, error) {
+	query := `SELECT ` + orderColumns + orderJoins + `
+		WHERE $1 = '' OR o.status = $1
+		ORDER BY o.created_at
+		LIMIT 500`
+
+	return or.queryOrders(ctx, query, status)
+}
+
+func (or *OrderRepository) queryOrders(ctx context.Context, query string, args ...interface{}) ([]models.Order, error) {
+	rows, err := or.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("queryOrders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		var o models.Order
+		err := rows.Scan(&o.ID, &o.UserID, &o.PaidBy, &o.PurchaseID, &o.MerchID, &o.Name, &o.VariantID, &o.SKU,
+			&o.Quantity, &o.UnitPrice, &o.Office, &o.Status, &o.CreatedAt, &o.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("queryOrders scan: %w", err)
+		}
+		orders = append(orders, o)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("queryOrders rows error: %w", rows.Err())
+	}
+
+	return orders, nil
+}
+
+// UpdateOrderStatus переводит заказ в статус status, если такой переход разрешен.
+// При отмене мерч возвращается на склад и списывается из инвентаря,
+// а монеты возвращаются тому, кто заплатил.
+func (or *OrderRepository) UpdateOrderStatus(ctx context.Context, id int, status string) (models.Order, error) {
+	tx, err := or.db.Begin(ctx)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var o models.Order
+	err = tx.QueryRow(ctx, `
+		SELECT id, user_id, paid_by, purchase_id, merch_id, variant_id, quantity, unit_price, status
+		FROM "MerchStore".orders
+		WHERE id = $1
+		FOR UPDATE`, id).
+		Scan(&o.ID, &o.UserID, &o.PaidBy, &o.PurchaseID, &o.MerchID, &o.VariantID, &o.Quantity, &o.UnitPrice, &o.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Order{}, ErrOrderNotFound
+		}
+		return models.Order{}, fmt.Errorf("UpdateOrderStatus: failed to get order: %w", err)
+	}
+
+	if !models.CanTransitionOrder(o.Status, status) {
+		return models.Order{}, ErrInvalidOrderTransition
+	}
+
+	if status == models.OrderCancelled {
+		if err := cancelOrder(ctx, tx, o); err != nil {
+			return models.Order{}, fmt.Errorf("UpdateOrderStatus: %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE "MerchStore".orders
+		SET status = $2, updated_at = now()
+		WHERE id = $1
+		RETURNING status, updated_at`, id, status).Scan(&o.Status, &o.UpdatedAt)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("UpdateOrderStatus: failed to update order: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Order{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return o, nil
+}
+
+// lockPurchaseOrder блокирует заказ, созданный по покупке purchaseID.
+// У покупок, сделанных до появления заказов, заказа нет - тогда found равно false.
+func lockPurchaseOrder(ctx context.Context, tx pgx.Tx, purchaseID int) (o models.Order, found bool, err error) {
+	err = tx.QueryRow(ctx, `
+		SELECT id, user_id, paid_by, purchase_id, merch_id, variant_id, quantity, unit_price, status
+		FROM "MerchStore".orders
+		WHERE purchase_id = $1
+		ORDER BY id DESC
+		LIMIT 1
+		FOR UPDATE`, purchaseID).
+		Scan(&o.ID, &o.UserID, &o.PaidBy, &o.PurchaseID, &o.MerchID, &o.VariantID, &o.Quantity, &o.UnitPrice, &o.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Order{}, false, nil
+		}
+		return models.Order{}, false, fmt.Errorf("failed to lock order: %w", err)
+	}
+	return o, true, nil
+}
+
+// cancelOrder откатывает покупку по заказу: инвентарь, склад, промокод и баланс плательщика.
+func cancelOrder(ctx context.Context, tx pgx.Tx, o models.Order) error {
+	// Если мерч уже вернули через /api/return, повторно монеты не возвращаем
+	var discount int
+	err := tx.QueryRow(ctx, `
+		UPDATE "MerchStore".purchases
+		SET returned_quantity = returned_quantity + $2
+		WHERE id = $1 AND quantity - returned_quantity >= $2
+		RETURNING discount`, o.PurchaseID, o.Quantity).Scan(&discount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPurchaseNotFound
+		}
+		return fmt.Errorf("failed to update purchase: %w", err)
+	}
+
+	if err := releasePromoCode(ctx, tx, o.PurchaseID); err != nil {
+		return err
+	}
+
+	if err := putBackToStock(ctx, tx, o.MerchID, o.VariantID, o.Quantity); err != nil {
+		return err
+	}
+
+	refund := o.UnitPrice * o.Quantity
+	_, err = tx.Exec(ctx, `UPDATE "MerchStore".users SET balance = balance + $1 WHERE id = $2`, refund, o.PaidBy)
+	if err != nil {
+		return fmt.Errorf("failed to update user balance: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO "MerchStore".ledger (user_id, movement_type, amount, reference_id, discount)
+		VALUES ($1, $2, $3, $4, $5)`, o.PaidBy, models.MovementOrderRefund, refund, o.ID, discount*o.Quantity)
+	if err != nil {
+		return fmt.Errorf("failed to insert into ledger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"EmployeeMerchStore/internal/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type PromotionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPromotionRepository(db *pgxpool.Pool) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+// ListPromotions возвращает акции. С activeOnly - только действующие сейчас.
+func (pr *PromotionRepository) ListPromotions(ctx context.Context, activeOnly bool) ([]models.Promotion, error) {
+	query := `
+		SELECT p.id, p.name, p.merch_id, COALESCE(c.slug, ''), p.discount_type, p.discount_value,
+		       p.starts_at, p.ends_at, p.created_at
+		FROM "MerchStore".promotions p
+		LEFT JOIN "MerchStore".categories c ON c.id = p.category_id
+		WHERE NOT $1 OR (p.starts_at <= now() AND p.ends_at > now())
+		ORDER BY p.starts_at DESC`
+
+	rows, err := pr.db.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("ListPromotions: %w", err)
+	}
+	defer rows.Close()
+
+	var promotions []models.Promotion
+	for rows.Next() {
+		var p models.Promotion
+		err := rows.Scan(&p.ID, &p.Name, &p.MerchID, &p.Category, &p.DiscountType, &p.DiscountValue,
+			&p.StartsAt, &p.EndsAt, &p.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ListPromotions scan: %w", err)
+		}
+		promotions = append(promotions, p)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("ListPromotions rows error: %w", rows.Err())
+	}
+
+	return promotions, nil
+}
+
+func (pr *PromotionRepository) CreatePromotion(ctx context.Context, p models.Promotion) (int, error) {
+	var categoryID *int
+	if p.Category != "" {
+		var cid int
+		err := pr.db.QueryRow(ctx, `SELECT id FROM "MerchStore".categories WHERE slug = $1`, p.Category).Scan(&cid)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, fmt.Errorf("CreatePromotion: %w", ErrCategoryNotFound)
+			}
+			return 0, fmt.Errorf("CreatePromotion: %w", err)
+		}
+		categoryID = &cid
+	}
+
+	var id int
+	err := pr.db.QueryRow(ctx, `
+		INSERT INTO "MerchStore".promotions (name, merch_id, category_id, discount_type, discount_value, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`, p.Name, p.MerchID, categoryID, p.DiscountType, p.DiscountValue, p.StartsAt, p.EndsAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("CreatePromotion: %w", err)
+	}
+	return id, nil
+}
+
+// EndPromotion досрочно завершает акцию. Акцию не удаляем: на нее ссылаются покупки.
+func (pr *PromotionRepository) EndPromotion(ctx context.Context, id int) error {
+	ct, err := pr.db.Exec(ctx, `
+		UPDATE "MerchStore".promotions
+		SET ends_at = GREATEST(starts_at + interval '1 second', LEAST(ends_at, now()))
+		WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("EndPromotion: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("EndPromotion: %w", ErrPromotionNotFound)
+	}
+	return nil
+}
+
+func (pr *PromotionRepository) ListPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	query := `
+		SELECT id, code, merch_id, discount_type, discount_value, max_uses, used_count, starts_at, ends_at, created_at
+		FROM "MerchStore".promo_codes
+		ORDER BY created_at DESC`
+
+	rows, err := pr.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ListPromoCodes: %w", err)
+	}
+	defer rows.Close()
+
+	var codes []models.PromoCode
+	for rows.Next() {
+		var pc models.PromoCode
+		err := rows.Scan(&pc.ID, &pc.Code, &pc.MerchID, &pc.DiscountType, &pc.DiscountValue,
+			&pc.MaxUses, &pc.UsedCount, &pc.StartsAt, &pc.EndsAt, &pc.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ListPromoCodes scan: %w", err)
+		}
+		codes = append(codes, pc)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("ListPromoCodes rows error: %w", rows.Err())
+	}
+
+	return codes, nil
+}
+
+func (pr *PromotionRepository) CreatePromoCode(ctx context.Context, pc models.PromoCode) (int, error) {
+	var id int
+	err := pr.db.QueryRow(ctx, `
+		INSERT INTO "MerchStore".promo_codes (code, merch_id, discount_type, discount_value, max_uses, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`, pc.Code, pc.MerchID, pc.DiscountType, pc.DiscountValue, pc.MaxUses, pc.StartsAt, pc.EndsAt).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("CreatePromoCode: %w", err)
+	}
+	return id, nil
+}
+
+// DisablePromoCode закрывает промокод для новых покупок.
+func (pr *PromotionRepository) DisablePromoCode(ctx context.Context, id int) error {
+	ct, err := pr.db.Exec(ctx, `
+		UPDATE "MerchStore".promo_codes
+		SET ends_at = LEAST(COALESCE(ends_at, now()), now())
+		WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("DisablePromoCode: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("DisablePromoCode: %w", ErrPromoCodeNotFound)
+	}
+	return nil
+}
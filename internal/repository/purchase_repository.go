@@ -2,9 +2,12 @@ package repository
 
 import (
     "context"
+    "errors"
     "fmt"
+    "time"
 
     "EmployeeMerchStore/internal/models"
+    "github.com/jackc/pgx/v4"
     "github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -16,49 +19,45 @@ func NewPurchasesRepository(db *pgxpool.Pool) *PurchasesRepository {
     return &PurchasesRepository{db: db}
 }
 
-func (pr *PurchasesRepository) BuyMerch(ctx context.Context, userID string, merchID int, quantity, price int) error {
+// BuyMerch списывает монеты и склад и добавляет покупку в инвентарь пользователя.
+// Если у покупки есть вариант, остаток списывается с варианта, иначе - с самого мерча.
+func (pr *PurchasesRepository) BuyMerch(ctx context.Context, purchase *models.Purchase) error {
     tx, err := pr.db.Begin(ctx)
     if err != nil {
         return fmt.Errorf("failed to start transaction: %w", err)
     }
-    defer func() {
-        if err != nil {
-            tx.Rollback(ctx)
-        }
-    }()
-
-    // Добавляем покупку
-    purchaseQuery := `
-        INSERT INTO "MerchStore".purchases (user_id, merch_id, quantity, purchased_at)
-        VALUES ($1, $2, $3, now())
-        ON CONFLICT (user_id, merch_id)
-        DO UPDATE SET 
-            quantity = "MerchStore".purchases.quantity + EXCLUDED.quantity,
-            purchased_at = now();
-    `
-    _, err = tx.Exec(ctx, purchaseQuery, userID, merchID, quantity)
-    if err != nil {
-        return fmt.Errorf("BuyMerch: failed to insert/update purchase: %w", err)
-    }
+    defer tx.Rollback(ctx)
 
-    // Обновляем баланс пользователя
+    // Списываем монеты; проверка баланса в самом UPDATE не дает
+    // параллельным покупкам увести баланс в минус
     updateBalanceQuery := `
         UPDATE "MerchStore".users
         SET balance = balance - $1
-        WHERE id = $2
+        WHERE id = $2 AND balance >= $1
     `
-    totalCost := price * quantity
-    _, err = tx.Exec(ctx, updateBalanceQuery, totalCost, userID)
+    totalCost := purchase.UnitPrice * purchase.Quantity
+    ct, err := tx.Exec(ctx, updateBalanceQuery, totalCost, purchase.UserID)
     if err != nil {
         return fmt.Errorf("BuyMerch: failed to update user balance: %w", err)
     }
+    if ct.RowsAffected() == 0 {
+        return fmt.Errorf("BuyMerch: %w", ErrInsufficientFunds)
+    }
+
+    if err := addToInventory(ctx, tx, purchase); err != nil {
+        return fmt.Errorf("BuyMerch: %w", err)
+    }
+
+    if err := createOrder(ctx, tx, purchase, purchase.UserID); err != nil {
+        return fmt.Errorf("BuyMerch: %w", err)
+    }
 
     // Записываем в ledger
     ledgerQuery := `
-        INSERT INTO "MerchStore".ledger (user_id, movement_type, amount, reference_id, created_at)
-        VALUES ($1, 'purchase', $2, $3, now());
+        INSERT INTO "MerchStore".ledger (user_id, movement_type, amount, reference_id, discount, created_at)
+        VALUES ($1, 'purchase', $2, $3, $4, now());
     `
-    _, err = tx.Exec(ctx, ledgerQuery, userID, price, merchID)
+    _, err = tx.Exec(ctx, ledgerQuery, purchase.UserID, totalCost, purchase.MerchID, purchase.Discount*purchase.Quantity)
     if err != nil {
         return fmt.Errorf("BuyMerch: failed to insert into ledger: %w", err)
     }
@@ -71,29 +70,464 @@ func (pr *PurchasesRepository) BuyMerch(ctx context.Context, userID string, merc
     return nil
 }
 
+// GiftMerch покупает мерч за счет buyerID и кладет его в инвентарь purchase.UserID.
+// В ledger пишутся записи обеим сторонам, сообщение сохраняется в подарке.
+func (pr *PurchasesRepository) GiftMerch(ctx context.Context, purchase *models.Purchase, buyerID, message string) (int, error) {
+    tx, err := pr.db.Begin(ctx)
+    if err != nil {
+        return 0, fmt.Errorf("failed to start transaction: %w", err)
+    }
+    defer tx.Rollback(ctx)
+
+    totalCost := purchase.UnitPrice * purchase.Quantity
+    ct, err := tx.Exec(ctx, `
+        UPDATE "MerchStore".users
+        SET balance = balance - $1
+        WHERE id = $2 AND balance >= $1`, totalCost, buyerID)
+    if err != nil {
+        return 0, fmt.Errorf("GiftMerch: failed to update buyer balance: %w", err)
+    }
+    if ct.RowsAffected() == 0 {
+        return 0, ErrInsufficientFunds
+    }
+
+    if err := addToInventory(ctx, tx, purchase); err != nil {
+        return 0, fmt.Errorf("GiftMerch: %w", err)
+    }
+
+    if err := createOrder(ctx, tx, purchase, buyerID); err != nil {
+        return 0, fmt.Errorf("GiftMerch: %w", err)
+    }
+
+    var giftID int
+    err = tx.QueryRow(ctx, `
+        INSERT INTO "MerchStore".gifts (from_user_id, to_user_id, merch_id, variant_id, purchase_id, message)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+        RETURNING id`,
+        buyerID, purchase.UserID, purchase.MerchID, purchase.VariantID, purchase.ID, message).Scan(&giftID)
+    if err != nil {
+        return 0, fmt.Errorf("GiftMerch: failed to insert gift: %w", err)
+    }
+
+    _, err = tx.Exec(ctx, `
+        INSERT INTO "MerchStore".ledger (user_id, reference_id, reference_id_usr, movement_type, amount, discount)
+        VALUES ($1, $3, $2, $4, $5, $7), ($2, $3, $1, $6, $5, 0)`,
+        buyerID, purchase.UserID, giftID, models.MovementGiftSent, totalCost, models.MovementGiftReceived,
+        purchase.Discount*purchase.Quantity)
+    if err != nil {
+        return 0, fmt.Errorf("GiftMerch: failed to insert into ledger: %w", err)
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return 0, fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
+    return giftID, nil
+}
+
+// GetReceivedGifts возвращает последние подарки, полученные пользователем.
+func (pr *PurchasesRepository) GetReceivedGifts(ctx context.Context, userID string) ([]models.Gift, error) {
+    query := `
+        SELECT g.id, g.from_user_id, u.username, g.merch_id, m.name, g.variant_id,
+               COALESCE(v.sku, ''), COALESCE(g.message, ''), g.created_at
+        FROM "MerchStore".gifts g
+        JOIN "MerchStore".users u ON u.id = g.from_user_id
+        JOIN "MerchStore".merch m ON m.id = g.merch_id
+        LEFT JOIN "MerchStore".merch_variants v ON v.id = g.variant_id
+        WHERE g.to_user_id = $1
+        ORDER BY g.created_at DESC
+        LIMIT 100`
+
+    rows, err := pr.db.Query(ctx, query, userID)
+    if err != nil {
+        return nil, fmt.Errorf("GetReceivedGifts: %w", err)
+    }
+    defer rows.Close()
+
+    var gifts []models.Gift
+    for rows.Next() {
+        var g models.Gift
+        err := rows.Scan(&g.ID, &g.FromUserID, &g.FromUser, &g.MerchID, &g.Name, &g.VariantID,
+            &g.SKU, &g.Message, &g.CreatedAt)
+        if err != nil {
+            return nil, fmt.Errorf("GetReceivedGifts scan: %w", err)
+        }
+        gifts = append(gifts, g)
+    }
+
+    if rows.Err() != nil {
+        return nil, fmt.Errorf("GetReceivedGifts rows error: %w", rows.Err())
+    }
+
+    return gifts, nil
+}
+
+// addToInventory списывает мерч со склада и добавляет покупку отдельной строкой
+// в инвентарь purchase.UserID. Заполняет purchase.ID и purchase.Purchased.
+func addToInventory(ctx context.Context, tx pgx.Tx, purchase *models.Purchase) error {
+    if err := checkAvailable(ctx, tx, purchase.MerchID); err != nil {
+        return err
+    }
+
+    if err := checkPurchaseLimit(ctx, tx, purchase); err != nil {
+        return err
+    }
+
+    // Списываем со склада, если запас ограничен
+    if err := takeFromStock(ctx, tx, purchase.MerchID, purchase.VariantID, purchase.Quantity); err != nil {
+        return err
+    }
+
+    if purchase.PromoCodeID != nil {
+        if err := redeemPromoCode(ctx, tx, *purchase.PromoCodeID); err != nil {
+            return err
+        }
+    }
+
+    purchaseQuery := `
+        INSERT INTO "MerchStore".purchases (user_id, merch_id, variant_id, quantity, unit_price,
+                                           discount, promotion_id, promo_code_id, purchased_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now())
+        RETURNING id, purchased_at;
+    `
+    err := tx.QueryRow(ctx, purchaseQuery, purchase.UserID, purchase.MerchID, purchase.VariantID,
+        purchase.Quantity, purchase.UnitPrice, purchase.Discount, purchase.PromotionID, purchase.PromoCodeID).
+        Scan(&purchase.ID, &purchase.Purchased)
+    if err != nil {
+        return fmt.Errorf("failed to insert purchase: %w", err)
+    }
+    return nil
+}
+
+// checkAvailable проверяет, что товар не в архиве и сейчас попадает в окно доступности.
+func checkAvailable(ctx context.Context, tx pgx.Tx, merchID int) error {
+    var available bool
+    err := tx.QueryRow(ctx, `
+        SELECT archived_at IS NULL
+           AND (available_from IS NULL OR available_from <= now())
+           AND (available_until IS NULL OR available_until > now())
+        FROM "MerchStore".merch WHERE id = $1`, merchID).Scan(&available)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return ErrMerchNotFound
+        }
+        return fmt.Errorf("failed to check availability: %w", err)
+    }
+    if !available {
+        return ErrMerchUnavailable
+    }
+    return nil
+}
+
+// checkPurchaseLimit проверяет лимит покупок товара для purchase.UserID по истории покупок.
+// Возвращенные и отмененные единицы не считаются. Период календарный: с начала месяца, квартала или года.
+func checkPurchaseLimit(ctx context.Context, tx pgx.Tx, purchase *models.Purchase) error {
+    var limit *int
+    var period string
+    err := tx.QueryRow(ctx, `SELECT purchase_limit, limit_period FROM "MerchStore".merch WHERE id = $1`, purchase.MerchID).
+        Scan(&limit, &period)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return ErrMerchNotFound
+        }
+        return fmt.Errorf("failed to get purchase limit: %w", err)
+    }
+    if limit == nil {
+        return nil
+    }
+
+    // Блокируем пользователя, чтобы параллельные покупки не прошли лимит вдвоем
+    if _, err := tx.Exec(ctx, `SELECT 1 FROM "MerchStore".users WHERE id = $1 FOR UPDATE`, purchase.UserID); err != nil {
+        return fmt.Errorf("failed to lock user: %w", err)
+    }
+
+    var bought int
+    err = tx.QueryRow(ctx, `
+        SELECT COALESCE(SUM(quantity - returned_quantity), 0)
+        FROM "MerchStore".purchases
+        WHERE user_id = $1 AND merch_id = $2
+          AND purchased_at >= CASE WHEN $3 = 'lifetime' THEN '-infinity'::timestamp
+                                   ELSE date_trunc($3, now())::timestamp END`,
+        purchase.UserID, purchase.MerchID, period).Scan(&bought)
+    if err != nil {
+        return fmt.Errorf("failed to count purchases: %w", err)
+    }
+
+    if bought+purchase.Quantity > *limit {
+        return ErrPurchaseLimitExceeded
+    }
+    return nil
+}
+
+// redeemPromoCode списывает одно использование промокода.
+// Проверка лимита в самом UPDATE не дает двум параллельным покупкам превысить max_uses.
+func redeemPromoCode(ctx context.Context, tx pgx.Tx, id int) error {
+    ct, err := tx.Exec(ctx, `
+        UPDATE "MerchStore".promo_codes
+        SET used_count = used_count + 1
+        WHERE id = $1 AND (max_uses IS NULL OR used_count < max_uses)`, id)
+    if err != nil {
+        return fmt.Errorf("failed to redeem promo code: %w", err)
+    }
+    if ct.RowsAffected() == 0 {
+        return ErrPromoCodeExhausted
+    }
+    return nil
+}
+
+// releasePromoCode возвращает использование промокода, если покупка purchaseID
+// сделана с ним и теперь полностью возвращена. Счетчик не опускается ниже нуля.
+func releasePromoCode(ctx context.Context, tx pgx.Tx, purchaseID int) error {
+    _, err := tx.Exec(ctx, `
+        UPDATE "MerchStore".promo_codes c
+        SET used_count = GREATEST(c.used_count - 1, 0)
+        FROM "MerchStore".purchases p
+        WHERE p.id = $1 AND p.promo_code_id = c.id AND p.returned_quantity >= p.quantity`, purchaseID)
+    if err != nil {
+        return fmt.Errorf("failed to release promo code: %w", err)
+    }
+    return nil
+}
+
+// createOrder создает заказ на выдачу покупки в офисе purchase.Office.
+// paidBy - кто заплатил, ему вернутся монеты при отмене заказа. Заполняет purchase.OrderID.
+func createOrder(ctx context.Context, tx pgx.Tx, purchase *models.Purchase, paidBy string) error {
+    err := tx.QueryRow(ctx, `
+        INSERT INTO "MerchStore".orders (user_id, paid_by, purchase_id, merch_id, variant_id, quantity, unit_price, office, status)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+        RETURNING id`,
+        purchase.UserID, paidBy, purchase.ID, purchase.MerchID, purchase.VariantID,
+        purchase.Quantity, purchase.UnitPrice, purchase.Office, models.OrderPlaced).Scan(&purchase.OrderID)
+    if err != nil {
+        return fmt.Errorf("failed to create order: %w", err)
+    }
+    return nil
+}
+
+// takeFromStock списывает quantity единиц со склада варианта или мерча.
+func takeFromStock(ctx context.Context, tx pgx.Tx, merchID int, variantID *int, quantity int) error {
+    query := `
+        UPDATE "MerchStore".merch
+        SET stock = stock - $2
+        WHERE id = $1 AND (stock IS NULL OR stock >= $2)`
+    id := merchID
+    if variantID != nil {
+        query = `
+            UPDATE "MerchStore".merch_variants
+            SET stock = stock - $2
+            WHERE id = $1 AND (stock IS NULL OR stock >= $2)`
+        id = *variantID
+    }
+
+    ct, err := tx.Exec(ctx, query, id, quantity)
+    if err != nil {
+        return fmt.Errorf("failed to update stock: %w", err)
+    }
+    if ct.RowsAffected() == 0 {
+        return ErrOutOfStock
+    }
+    return nil
+}
+
+// putBackToStock возвращает quantity единиц на склад варианта или мерча.
+// У неограниченного запаса stock останется NULL. Если товар был исчерпан (для товара
+// с вариантами - все варианты), вишлистеры получают уведомление, как при пополнении
+// склада администратором.
+func putBackToStock(ctx context.Context, tx pgx.Tx, merchID int, variantID *int, quantity int) error {
+    query := `UPDATE "MerchStore".merch SET stock = stock + $2 WHERE id = $1 RETURNING stock`
+    id := merchID
+    if variantID != nil {
+        query = `UPDATE "MerchStore".merch_variants SET stock = stock + $2 WHERE id = $1 RETURNING stock`
+        id = *variantID
+    }
+
+    var stock *int
+    if err := tx.QueryRow(ctx, query, id, quantity).Scan(&stock); err != nil {
+        return fmt.Errorf("failed to update stock: %w", err)
+    }
+
+    // До возврата на складе было stock - quantity, то есть ноль
+    if stock == nil || quantity <= 0 || *stock != quantity {
+        return nil
+    }
+    if variantID != nil {
+        // Товар с вариантами закончился, только если распроданы и все остальные варианты
+        var othersSoldOut bool
+        err := tx.QueryRow(ctx, `
+            SELECT NOT EXISTS (
+                SELECT 1 FROM "MerchStore".merch_variants
+                WHERE merch_id = $1 AND id <> $2 AND (stock IS NULL OR stock > 0))`,
+            merchID, *variantID).Scan(&othersSoldOut)
+        if err != nil {
+            return fmt.Errorf("failed to check variants stock: %w", err)
+        }
+        if !othersSoldOut {
+            return nil
+        }
+    }
+    return notifyRestock(ctx, tx, merchID)
+}
+
+// notifyRestock уведомляет всех, у кого мерч в вишлисте, что он снова в наличии.
+// Текст тот же, что у MerchService.notifyRestock.
+func notifyRestock(ctx context.Context, tx pgx.Tx, merchID int) error {
+    _, err := tx.Exec(ctx, `
+        INSERT INTO "MerchStore".notifications (user_id, kind, merch_id, message)
+        SELECT w.user_id, $2, m.id, m.name || ' is back in stock'
+        FROM "MerchStore".wishlist w
+        JOIN "MerchStore".merch m ON m.id = w.merch_id
+        WHERE w.merch_id = $1`, merchID, models.NotificationRestock)
+    if err != nil {
+        return fmt.Errorf("failed to notify wishlisters: %w", err)
+    }
+    return nil
+}
+
+// GetMerchId ищет товар по имени. Активный товар важнее архивного, среди архивных берется
+// последний; купить архивный товар все равно не даст checkAvailable.
 func (pr *PurchasesRepository) GetMerchId(ctx context.Context, name string) (int, int, error) {
-    query := `SELECT id, price FROM "MerchStore".merch WHERE name = $1 LIMIT 1`
+    query := `
+        SELECT id, price FROM "MerchStore".merch
+        WHERE name = $1
+        ORDER BY archived_at IS NOT NULL, archived_at DESC
+        LIMIT 1`
 
     var merchID int
     var price int
     
     err := pr.db.QueryRow(ctx, query, name).Scan(&merchID, &price)
     if err != nil {
-        if err.Error() == "no rows in result set" {
-            return 0, 0, fmt.Errorf("Merch with name %s not found", name)
+        if errors.Is(err, pgx.ErrNoRows) {
+            return 0, 0, fmt.Errorf("GetMerchId %q: %w", name, ErrMerchNotFound)
         }
         return 0, 0, fmt.Errorf("GetMerchId: %w", err)
     }
     return merchID, price, nil
 }
 
-func (pr *PurchasesRepository) GetUserMerch(ctx context.Context, userID string) ([]*models.UserMerch, error) {
+// GetReturnableMerch находит по имени и SKU товар и вариант из еще не возвращенных покупок
+// пользователя, начиная с последней. Ищется среди своих покупок, а не по каталогу, чтобы
+// архивный товар можно было вернуть, даже если его имя занял новый товар.
+// Пустой sku - покупка без варианта.
+func (pr *PurchasesRepository) GetReturnableMerch(ctx context.Context, userID, name, sku string) (int, *int, error) {
     query := `
-        SELECT p.merch_id, m.name, m.price, p.quantity, p.purchased_at
+        SELECT p.merch_id, p.variant_id
         FROM "MerchStore".purchases p
-        JOIN "MerchStore".merch m ON p.merch_id = m.id
-        WHERE p.user_id = $1
-        ORDER BY p.purchased_at DESC;
+        JOIN "MerchStore".merch m ON m.id = p.merch_id
+        LEFT JOIN "MerchStore".merch_variants v ON v.id = p.variant_id
+        WHERE p.user_id = $1 AND m.name = $2 AND p.quantity > p.returned_quantity
+          AND (($3 = '' AND p.variant_id IS NULL) OR v.sku = $3)
+        ORDER BY p.purchased_at DESC, p.id DESC
+        LIMIT 1`
+
+    var merchID int
+    var variantID *int
+    err := pr.db.QueryRow(ctx, query, userID, name, sku).Scan(&merchID, &variantID)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return 0, nil, ErrPurchaseNotFound
+        }
+        return 0, nil, fmt.Errorf("GetReturnableMerch: %w", err)
+    }
+    return merchID, variantID, nil
+}
+
+// GetVariant ищет вариант мерча по SKU.
+func (pr *PurchasesRepository) GetVariant(ctx context.Context, merchID int, sku string) (models.MerchVariant, error) {
+    query := `
+        SELECT v.id, v.merch_id, v.sku, COALESCE(v.size, ''), COALESCE(v.colour, ''),
+               v.price_delta, m.price + v.price_delta, v.stock, v.created_at
+        FROM "MerchStore".merch_variants v
+        JOIN "MerchStore".merch m ON m.id = v.merch_id
+        WHERE v.merch_id = $1 AND v.sku = $2`
+
+    var v models.MerchVariant
+    err := pr.db.QueryRow(ctx, query, merchID, sku).
+        Scan(&v.ID, &v.MerchID, &v.SKU, &v.Size, &v.Colour, &v.PriceDelta, &v.Price, &v.Stock, &v.CreatedAt)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return models.MerchVariant{}, ErrVariantNotFound
+        }
+        return models.MerchVariant{}, fmt.Errorf("GetVariant: %w", err)
+    }
+    return v, nil
+}
+
+// GetActivePromotions возвращает акции, которые сейчас действуют на мерч merchID:
+// на сам мерч, на его категорию и на весь магазин.
+func (pr *PurchasesRepository) GetActivePromotions(ctx context.Context, merchID int) ([]models.Promotion, error) {
+    query := `
+        SELECT p.id, p.name, p.merch_id, COALESCE(c.slug, ''), p.discount_type, p.discount_value,
+               p.starts_at, p.ends_at, p.created_at
+        FROM "MerchStore".promotions p
+        LEFT JOIN "MerchStore".categories c ON c.id = p.category_id
+        WHERE (p.merch_id = $1
+               OR p.category_id = (SELECT category_id FROM "MerchStore".merch WHERE id = $1)
+               OR (p.merch_id IS NULL AND p.category_id IS NULL))
+          AND p.starts_at <= now() AND p.ends_at > now()`
+
+    rows, err := pr.db.Query(ctx, query, merchID)
+    if err != nil {
+        return nil, fmt.Errorf("GetActivePromotions: %w", err)
+    }
+    defer rows.Close()
+
+    var promotions []models.Promotion
+    for rows.Next() {
+        var p models.Promotion
+        err := rows.Scan(&p.ID, &p.Name, &p.MerchID, &p.Category, &p.DiscountType, &p.DiscountValue,
+            &p.StartsAt, &p.EndsAt, &p.CreatedAt)
+        if err != nil {
+            return nil, fmt.Errorf("GetActivePromotions scan: %w", err)
+        }
+        promotions = append(promotions, p)
+    }
+
+    if rows.Err() != nil {
+        return nil, fmt.Errorf("GetActivePromotions rows error: %w", rows.Err())
+    }
+
+    return promotions, nil
+}
+
+// GetPromoCode ищет промокод без учета регистра.
+func (pr *PurchasesRepository) GetPromoCode(ctx context.Context, code string) (models.PromoCode, error) {
+    query := `
+        SELECT id, code, merch_id, discount_type, discount_value, max_uses, used_count, starts_at, ends_at, created_at
+        FROM "MerchStore".promo_codes
+        WHERE upper(code) = upper($1)`
+
+    var pc models.PromoCode
+    err := pr.db.QueryRow(ctx, query, code).Scan(&pc.ID, &pc.Code, &pc.MerchID, &pc.DiscountType, &pc.DiscountValue,
+        &pc.MaxUses, &pc.UsedCount, &pc.StartsAt, &pc.EndsAt, &pc.CreatedAt)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return models.PromoCode{}, ErrPromoCodeNotFound
+        }
+        return models.PromoCode{}, fmt.Errorf("GetPromoCode: %w", err)
+    }
+    return pc, nil
+}
+
+// CountVariants возвращает число вариантов мерча. Мерч с вариантами нельзя купить без SKU.
+func (pr *PurchasesRepository) CountVariants(ctx context.Context, merchID int) (int, error) {
+    var count int
+    err := pr.db.QueryRow(ctx, `SELECT count(*) FROM "MerchStore".merch_variants WHERE merch_id = $1`, merchID).Scan(&count)
+    if err != nil {
+        return 0, fmt.Errorf("CountVariants: %w", err)
+    }
+    return count, nil
+}
+
+func (pr *PurchasesRepository) GetUserMerch(ctx context.Context, userID string) ([]*models.UserMerch, error) {
+    query := `
+        SELECT i.merch_id, m.name, m.price + COALESCE(v.price_delta, 0), i.variant_id,
+               COALESCE(v.sku, ''), COALESCE(v.size, ''), COALESCE(v.colour, ''), i.quantity, i.last_purchased_at
+        FROM "MerchStore".user_inventory i
+        JOIN "MerchStore".merch m ON i.merch_id = m.id
+        LEFT JOIN "MerchStore".merch_variants v ON i.variant_id = v.id
+        WHERE i.user_id = $1
+        ORDER BY i.last_purchased_at DESC;
     `
 
     rows, err := pr.db.Query(ctx, query, userID)
@@ -105,7 +539,8 @@ func (pr *PurchasesRepository) GetUserMerch(ctx context.Context, userID string)
     var merchList []*models.UserMerch
     for rows.Next() {
         var um models.UserMerch
-        err := rows.Scan(&um.MerchID, &um.Name, &um.Price, &um.Quantity, &um.PurchasedAt)
+        err := rows.Scan(&um.MerchID, &um.Name, &um.Price, &um.VariantID,
+            &um.SKU, &um.Size, &um.Colour, &um.Quantity, &um.PurchasedAt)
         if err != nil {
             return nil, fmt.Errorf("GetUserMerch scan: %w", err)
         }
@@ -118,3 +553,137 @@ func (pr *PurchasesRepository) GetUserMerch(ctx context.Context, userID string)
 
     return merchList, nil
 }
+
+// ReturnMerch возвращает одну единицу мерча из последней покупки и зачисляет цену,
+// по которой ее купили, тому, кто за нее платил: для подарка это даритель, а не получатель.
+// Вернуть можно только в течение window после этой покупки. Если заказ по покупке еще
+// не выдан, он отменяется, чтобы мерч нельзя было получить после возврата монет.
+// Возвращает сумму возврата.
+func (pr *PurchasesRepository) ReturnMerch(ctx context.Context, userID string, merchID int, variantID *int, window time.Duration) (int, error) {
+    tx, err := pr.db.Begin(ctx)
+    if err != nil {
+        return 0, fmt.Errorf("failed to start transaction: %w", err)
+    }
+    defer tx.Rollback(ctx)
+
+    var purchaseID, refund, discount int
+    var payerID string
+    var inWindow bool
+    err = tx.QueryRow(ctx, `
+        SELECT p.id, p.unit_price, p.discount, p.purchased_at >= now() - make_interval(secs => $3),
+               COALESCE(
+                   (SELECT o.paid_by FROM "MerchStore".orders o WHERE o.purchase_id = p.id LIMIT 1),
+                   (SELECT g.from_user_id FROM "MerchStore".gifts g WHERE g.purchase_id = p.id LIMIT 1),
+                   p.user_id)
+        FROM "MerchStore".purchases p
+        WHERE p.user_id = $1 AND p.merch_id = $2 AND p.variant_id IS NOT DISTINCT FROM $4
+          AND p.quantity > p.returned_quantity
+        ORDER BY p.purchased_at DESC, p.id DESC
+        LIMIT 1`, userID, merchID, window.Seconds(), variantID).Scan(&purchaseID, &refund, &discount, &inWindow, &payerID)
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return 0, ErrPurchaseNotFound
+        }
+        return 0, fmt.Errorf("ReturnMerch: failed to get purchase: %w", err)
+    }
+    if !inWindow {
+        return 0, ErrReturnWindowExpired
+    }
+
+    // Заказ блокируем раньше покупки, в том же порядке, что и UpdateOrderStatus
+    order, hasOrder, err := lockPurchaseOrder(ctx, tx, purchaseID)
+    if err != nil {
+        return 0, fmt.Errorf("ReturnMerch: %w", err)
+    }
+    if hasOrder && order.Status != models.OrderDelivered && order.Status != models.OrderCancelled {
+        if err := cancelOrder(ctx, tx, order); err != nil {
+            return 0, fmt.Errorf("ReturnMerch: %w", err)
+        }
+        _, err = tx.Exec(ctx, `
+            UPDATE "MerchStore".orders SET status = $2, updated_at = now() WHERE id = $1`, order.ID, models.OrderCancelled)
+        if err != nil {
+            return 0, fmt.Errorf("ReturnMerch: failed to cancel order: %w", err)
+        }
+        if err := tx.Commit(ctx); err != nil {
+            return 0, fmt.Errorf("failed to commit transaction: %w", err)
+        }
+        return order.UnitPrice * order.Quantity, nil
+    }
+
+    // Строку покупки не удаляем: она остается в истории покупок
+    ct, err := tx.Exec(ctx, `
+        UPDATE "MerchStore".purchases SET returned_quantity = returned_quantity + 1
+        WHERE id = $1 AND quantity > returned_quantity`, purchaseID)
+    if err != nil {
+        return 0, fmt.Errorf("ReturnMerch: failed to update purchase: %w", err)
+    }
+    if ct.RowsAffected() == 0 {
+        // Покупку успели вернуть или отменить параллельно
+        return 0, ErrPurchaseNotFound
+    }
+
+    if err := releasePromoCode(ctx, tx, purchaseID); err != nil {
+        return 0, fmt.Errorf("ReturnMerch: %w", err)
+    }
+
+    if err := putBackToStock(ctx, tx, merchID, variantID, 1); err != nil {
+        return 0, fmt.Errorf("ReturnMerch: %w", err)
+    }
+
+    _, err = tx.Exec(ctx, `UPDATE "MerchStore".users SET balance = balance + $1 WHERE id = $2`, refund, payerID)
+    if err != nil {
+        return 0, fmt.Errorf("ReturnMerch: failed to update user balance: %w", err)
+    }
+
+    // Возвращается цена со скидкой, поэтому скидку пишем и в запись о возврате
+    _, err = tx.Exec(ctx, `
+        INSERT INTO "MerchStore".ledger (user_id, movement_type, amount, reference_id, discount)
+        VALUES ($1, $2, $3, $4, $5)`, payerID, models.MovementRefund, refund, purchaseID, discount)
+    if err != nil {
+        return 0, fmt.Errorf("ReturnMerch: failed to insert into ledger: %w", err)
+    }
+
+    if err := tx.Commit(ctx); err != nil {
+        return 0, fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
+    return refund, nil
+}
+
+// GetPurchaseHistory возвращает все покупки пользователя, включая возвращенные,
+// с ценой на момент покупки и статусом заказа.
+func (pr *PurchasesRepository) GetPurchaseHistory(ctx context.Context, userID string) ([]models.PurchaseHistoryItem, error) {
+    query := `
+        SELECT p.id, p.merch_id, m.name, p.variant_id, COALESCE(v.sku, ''), p.quantity, p.returned_quantity,
+               p.unit_price, p.discount, p.purchased_at, o.id, COALESCE(o.status, '')
+        FROM "MerchStore".purchases p
+        JOIN "MerchStore".merch m ON m.id = p.merch_id
+        LEFT JOIN "MerchStore".merch_variants v ON v.id = p.variant_id
+        LEFT JOIN "MerchStore".orders o ON o.purchase_id = p.id
+        WHERE p.user_id = $1
+        ORDER BY p.purchased_at DESC, p.id DESC
+        LIMIT 500`
+
+    rows, err := pr.db.Query(ctx, query, userID)
+    if err != nil {
+        return nil, fmt.Errorf("GetPurchaseHistory: %w", err)
+    }
+    defer rows.Close()
+
+    var history []models.PurchaseHistoryItem
+    for rows.Next() {
+        var h models.PurchaseHistoryItem
+        err := rows.Scan(&h.ID, &h.MerchID, &h.Name, &h.VariantID, &h.SKU, &h.Quantity, &h.Returned,
+            &h.UnitPrice, &h.Discount, &h.PurchasedAt, &h.OrderID, &h.OrderStatus)
+        if err != nil {
+            return nil, fmt.Errorf("GetPurchaseHistory scan: %w", err)
+        }
+        history = append(history, h)
+    }
+
+    if rows.Err() != nil {
+        return nil, fmt.Errorf("GetPurchaseHistory rows error: %w", rows.Err())
+    }
+
+    return history, nil
+}
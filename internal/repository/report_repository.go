@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"EmployeeMerchStore/internal/models"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ReportRepository - выборки для сверки балансов и выгрузки отчетов.
+type ReportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepository(db *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// ReconcileBalances сравнивает баланс каждого пользователя с initialBalance плюс движения
+// в ledger и возвращает тех, у кого они расходятся.
+func (rr *ReportRepository) ReconcileBalances(ctx context.Context, initialBalance int) ([]models.BalanceMismatch, error) {
+	query := `
+		SELECT u.id, u.username, u.balance, $1 + COALESCE(l.delta, 0)
+		FROM "MerchStore".users u
+		LEFT JOIN (
+			SELECT user_id,
+			       SUM(CASE WHEN movement_type = ANY($2) THEN amount
+			                WHEN movement_type = ANY($3) THEN -amount
+			                ELSE 0 END)::integer AS delta
+			FROM "MerchStore".ledger
+			GROUP BY user_id
+		) l ON l.user_id = u.id
+		WHERE u.balance <> $1 + COALESCE(l.delta, 0)
+		ORDER BY u.username`
+
+	rows, err := rr.db.Query(ctx, query, initialBalance, models.CreditMovements, models.DebitMovements)
+	if err != nil {
+		return nil, fmt.Errorf("ReconcileBalances: %w", err)
+	}
+	defer rows.Close()
+
+	var mismatches []models.BalanceMismatch
+	for rows.Next() {
+		var m models.BalanceMismatch
+		if err := rows.Scan(&m.UserID, &m.Username, &m.Balance, &m.Expected); err != nil {
+			return nil, fmt.Errorf("ReconcileBalances scan: %w", err)
+		}
+		mismatches = append(mismatches, m)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("ReconcileBalances rows error: %w", rows.Err())
+	}
+
+	return mismatches, nil
+}
+
+func (rr *ReportRepository) GetBalances(ctx context.Context) ([]models.UserBalance, error) {
+	query := `
+		SELECT id, username, balance, is_admin, created_at
+		FROM "MerchStore".users
+		ORDER BY username`
+
+	rows, err := rr.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("GetBalances: %w", err)
+	}
+	defer rows.Close()
+
+	var balances []models.UserBalance
+	for rows.Next() {
+		var b models.UserBalance
+		if err := rows.Scan(&b.UserID, &b.Username, &b.Balance, &b.IsAdmin, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("GetBalances scan: %w", err)
+		}
+		balances = append(balances, b)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("GetBalances rows error: %w", rows.Err())
+	}
+
+	return balances, nil
+}
+
+// GetPurchases возвращает покупки всех пользователей за [from, to).
+func (rr *ReportRepository) GetPurchases(ctx context.Context, from, to time.Time) ([]models.PurchaseReportRow, error) {
+	query := `
+		SELECT p.id, p.purchased_at, u.username, m.name, COALESCE(v.sku, ''), p.quantity, p.returned_quantity,
+		       p.unit_price, p.discount, COALESCE(o.office, ''), COALESCE(o.status, '')
+		FROM "MerchStore".purchases p
+		JOIN "MerchStore".users u ON u.id = p.user_id
+		JOIN "MerchStore".merch m ON m.id = p.merch_id
+		LEFT JOIN "MerchStore".merch_variants v ON v.id = p.variant_id
+		LEFT JOIN "MerchStore".orders o ON o.purchase_id = p.id
+		WHERE p.purchased_at >= $1 AND p.purchased_at < $2
+		ORDER BY p.purchased_at, p.id`
+
+	rows, err := rr.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("GetPurchases: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []models.PurchaseReportRow
+	for rows.Next() {
+		var r models.PurchaseReportRow
+		err := rows.Scan(&r.PurchaseID, &r.PurchasedAt, &r.Username, &r.Merch, &r.SKU, &r.Quantity, &r.Returned,
+			&r.UnitPrice, &r.Discount, &r.Office, &r.OrderStatus)
+		if err != nil {
+			return nil, fmt.Errorf("GetPurchases scan: %w", err)
+		}
+		purchases = append(purchases, r)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("GetPurchases rows error: %w", rows.Err())
+	}
+
+	return purchases, nil
+}
@@ -37,6 +37,29 @@ func (ur *UserRepository) GetBalance(ctx context.Context, id string) (int, error
 	return balance, nil
 }
 
+func (ur *UserRepository) IsAdmin(ctx context.Context, id string) (bool, error) {
+	query := `SELECT is_admin FROM "MerchStore".users WHERE id = $1`
+
+	var isAdmin bool
+
+	if err := ur.db.QueryRow(ctx, query, id).Scan(&isAdmin); err != nil {
+		return false, fmt.Errorf("IsAdmin: %w", err)
+	}
+
+	return isAdmin, nil
+}
+
+func (ur *UserRepository) SetAdmin(ctx context.Context, id string, admin bool) error {
+	ct, err := ur.db.Exec(ctx, `UPDATE "MerchStore".users SET is_admin = $2 WHERE id = $1`, id, admin)
+	if err != nil {
+		return fmt.Errorf("SetAdmin: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("SetAdmin: %w", ErrUserNotFound)
+	}
+	return nil
+}
+
 func (ur *UserRepository) CreateUser(ctx context.Context, id, username, hashPswd string, balance int) error {
     tx, err := ur.db.Begin(ctx)
     if err != nil {
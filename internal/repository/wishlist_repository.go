@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"EmployeeMerchStore/internal/models"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type WishlistRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWishlistRepository(db *pgxpool.Pool) *WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+func (wr *WishlistRepository) AddToWishlist(ctx context.Context, userID string, merchID int) error {
+	query := `
+		INSERT INTO "MerchStore".wishlist (user_id, merch_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, merch_id) DO NOTHING`
+	if _, err := wr.db.Exec(ctx, query, userID, merchID); err != nil {
+		return fmt.Errorf("AddToWishlist: %w", err)
+	}
+	return nil
+}
+
+func (wr *WishlistRepository) RemoveFromWishlist(ctx context.Context, userID string, merchID int) error {
+	query := `DELETE FROM "MerchStore".wishlist WHERE user_id = $1 AND merch_id = $2`
+	ct, err := wr.db.Exec(ctx, query, userID, merchID)
+	if err != nil {
+		return fmt.Errorf("RemoveFromWishlist: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("RemoveFromWishlist: %w", ErrWishlistItemNotFound)
+	}
+	return nil
+}
+
+func (wr *WishlistRepository) GetWishlist(ctx context.Context, userID string) ([]models.WishlistItem, error) {
+	query := `
+		SELECT w.merch_id, m.name, m.price, m.stock, w.created_at
+		FROM "MerchStore".wishlist w
+		JOIN "MerchStore".merch m ON m.id = w.merch_id
+		WHERE w.user_id = $1
+		ORDER BY w.created_at`
+
+	rows, err := wr.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("GetWishlist: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.WishlistItem
+	for rows.Next() {
+		var item models.WishlistItem
+		if err := rows.Scan(&item.MerchID, &item.Name, &item.Price, &item.Stock, &item.AddedAt); err != nil {
+			return nil, fmt.Errorf("GetWishlist scan: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("GetWishlist rows error: %w", rows.Err())
+	}
+
+	return items, nil
+}
+
+// NotifyWishlisters создает уведомление каждому, у кого мерч в вишлисте.
+// Возвращает число созданных уведомлений.
+func (wr *WishlistRepository) NotifyWishlisters(ctx context.Context, merchID int, kind, message string) (int, error) {
+	query := `
+		INSERT INTO "MerchStore".notifications (user_id, kind, merch_id, message)
+		SELECT user_id, $2, merch_id, $3
+		FROM "MerchStore".wishlist
+		WHERE merch_id = $1`
+	ct, err := wr.db.Exec(ctx, query, merchID, kind, message)
+	if err != nil {
+		return 0, fmt.Errorf("NotifyWishlisters: %w", err)
+	}
+	return int(ct.RowsAffected()), nil
+}
+
+func (wr *WishlistRepository) GetNotifications(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error) {
+	query := `
+		SELECT id, kind, merch_id, message, created_at, read_at
+		FROM "MerchStore".notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT 100`
+
+	rows, err := wr.db.Query(ctx, query, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("GetNotifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.Kind, &n.MerchID, &n.Message, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, fmt.Errorf("GetNotifications scan: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("GetNotifications rows error: %w", rows.Err())
+	}
+
+	return notifications, nil
+}
+
+func (wr *WishlistRepository) MarkNotificationRead(ctx context.Context, userID string, id int) error {
+	query := `
+		UPDATE "MerchStore".notifications
+		SET read_at = COALESCE(read_at, now())
+		WHERE id = $1 AND user_id = $2`
+	ct, err := wr.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("MarkNotificationRead: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("MarkNotificationRead: %w", ErrNotificationNotFound)
+	}
+	return nil
+}
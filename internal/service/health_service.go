@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"EmployeeMerchStore/internal/models"
+)
+
+// readinessTimeout - сколько ждем каждую проверку, чтобы зависшая БД не вешала пробу.
+const readinessTimeout = 2 * time.Second
+
+// Pinger проверяет соединение с БД, его реализует *pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// MigrationChecker сообщает, сколько миграций еще не применено, его реализует *database.Migrator.
+type MigrationChecker interface {
+	Pending(ctx context.Context) (int, error)
+}
+
+// HealthService отвечает на пробы оркестратора.
+type HealthService struct {
+	db           Pinger
+	migrations   MigrationChecker
+	shuttingDown atomic.Bool
+}
+
+func NewHealthService(db Pinger, migrations MigrationChecker) *HealthService {
+	return &HealthService{db: db, migrations: migrations}
+}
+
+// SetShuttingDown помечает сервис как останавливающийся: readiness начинает падать,
+// и балансировщик перестает слать новые запросы, пока текущие дорабатывают.
+func (hs *HealthService) SetShuttingDown() {
+	hs.shuttingDown.Store(true)
+}
+
+// Readiness выполняет все проверки и возвращает true, если прошли все.
+func (hs *HealthService) Readiness(ctx context.Context) (bool, []models.HealthCheck) {
+	checks := []models.HealthCheck{
+		healthCheck("shutdown", func() error {
+			if hs.shuttingDown.Load() {
+				return errors.New("server is shutting down")
+			}
+			return nil
+		}),
+		healthCheck("database", func() error {
+			ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+			defer cancel()
+			return hs.db.Ping(ctx)
+		}),
+		healthCheck("migrations", func() error {
+			ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+			defer cancel()
+			pending, err := hs.migrations.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d migrations pending", pending)
+			}
+			return nil
+		}),
+	}
+
+	ready := true
+	for _, c := range checks {
+		ready = ready && c.OK
+	}
+	return ready, checks
+}
+
+func healthCheck(name string, fn func() error) models.HealthCheck {
+	if err := fn(); err != nil {
+		return models.HealthCheck{Name: name, OK: false, Error: err.Error()}
+	}
+	return models.HealthCheck{Name: name, OK: true}
+}
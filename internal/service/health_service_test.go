@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPinger struct {
+	mock.Mock
+}
+
+func (m *MockPinger) Ping(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+type MockMigrationChecker struct {
+	mock.Mock
+}
+
+func (m *MockMigrationChecker) Pending(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestReadiness_AllChecksPass(t *testing.T) {
+	db := new(MockPinger)
+	migrations := new(MockMigrationChecker)
+	db.On("Ping", mock.Anything).Return(nil)
+	migrations.On("Pending", mock.Anything).Return(0, nil)
+
+	ready, checks := NewHealthService(db, migrations).Readiness(context.Background())
+	assert.True(t, ready)
+	assert.Len(t, checks, 3)
+}
+
+func TestReadiness_DatabaseDown(t *testing.T) {
+	db := new(MockPinger)
+	migrations := new(MockMigrationChecker)
+	db.On("Ping", mock.Anything).Return(errors.New("connection refused"))
+	migrations.On("Pending", mock.Anything).Return(0, errors.New("connection refused"))
+
+	ready, checks := NewHealthService(db, migrations).Readiness(context.Background())
+	assert.False(t, ready)
+	assert.Equal(t, "database", checks[1].Name)
+	assert.Equal(t, "connection refused", checks[1].Error)
+}
+
+func TestReadiness_PendingMigrations(t *testing.T) {
+	db := new(MockPinger)
+	migrations := new(MockMigrationChecker)
+	db.On("Ping", mock.Anything).Return(nil)
+	migrations.On("Pending", mock.Anything).Return(2, nil)
+
+	ready, checks := NewHealthService(db, migrations).Readiness(context.Background())
+	assert.False(t, ready)
+	assert.Equal(t, "2 migrations pending", checks[2].Error)
+}
+
+func TestReadiness_ShuttingDown(t *testing.T) {
+	db := new(MockPinger)
+	migrations := new(MockMigrationChecker)
+	db.On("Ping", mock.Anything).Return(nil)
+	migrations.On("Pending", mock.Anything).Return(0, nil)
+
+	hs := NewHealthService(db, migrations)
+	hs.SetShuttingDown()
+
+	ready, checks := hs.Readiness(context.Background())
+	assert.False(t, ready)
+	assert.False(t, checks[0].OK)
+}
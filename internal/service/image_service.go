@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+
+	"EmployeeMerchStore/config"
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/storage"
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+)
+
+const (
+	defaultMaxImageSize  = 5 << 20 // 5 МБ
+	defaultThumbnailSize = 256
+	maxImagePixels       = 40_000_000 // защита от картинок, которые распаковываются в гигабайты
+)
+
+var (
+	ErrImageTooLarge    = errors.New("image is too large")
+	ErrUnsupportedImage = errors.New("unsupported image format, use jpeg, png or gif")
+)
+
+type ImageService struct {
+	MerchRepo repository.MerchRepositoryInterface
+	Storage   storage.Storage
+	config    *config.Config
+	logger    *slog.Logger
+}
+
+func NewImageService(merchRepo repository.MerchRepositoryInterface, storage storage.Storage, config *config.Config, logger *slog.Logger) *ImageService {
+	return &ImageService{
+		MerchRepo: merchRepo,
+		Storage:   storage,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// Upload сохраняет картинку товара и ее миниатюру в хранилище и привязывает их к товару.
+func (is *ImageService) Upload(ctx context.Context, merchID int, r io.Reader) (models.MerchImage, error) {
+	if _, err := is.MerchRepo.GetMerch(ctx, merchID); err != nil {
+		return models.MerchImage{}, fmt.Errorf("failed to get merch %d: %w", merchID, err)
+	}
+
+	maxSize := is.MaxSize()
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return models.MerchImage{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return models.MerchImage{}, ErrImageTooLarge
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return models.MerchImage{}, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return models.MerchImage{}, ErrImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return models.MerchImage{}, ErrUnsupportedImage
+	}
+
+	thumb, thumbType, err := makeThumbnail(src, format, is.thumbnailSize())
+	if err != nil {
+		return models.MerchImage{}, fmt.Errorf("failed to make thumbnail: %w", err)
+	}
+
+	name := uuid.NewString()
+	img := models.MerchImage{
+		MerchID:      merchID,
+		Key:          fmt.Sprintf("merch/%d/%s.%s", merchID, name, format),
+		ThumbnailKey: fmt.Sprintf("merch/%d/%s_thumb.%s", merchID, name, extension(thumbType)),
+		ContentType:  "image/" + format,
+		Width:        cfg.Width,
+		Height:       cfg.Height,
+	}
+	img.URL = is.Storage.URL(img.Key)
+	img.ThumbnailURL = is.Storage.URL(img.ThumbnailKey)
+
+	if err := is.Storage.Put(ctx, img.Key, bytes.NewReader(data), int64(len(data)), img.ContentType); err != nil {
+		return models.MerchImage{}, fmt.Errorf("failed to store image: %w", err)
+	}
+	if err := is.Storage.Put(ctx, img.ThumbnailKey, bytes.NewReader(thumb), int64(len(thumb)), thumbType); err != nil {
+		is.removeFiles(ctx, img.Key)
+		return models.MerchImage{}, fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	img.ID, err = is.MerchRepo.AddImage(ctx, img)
+	if err != nil {
+		is.removeFiles(ctx, img.Key, img.ThumbnailKey)
+		return models.MerchImage{}, fmt.Errorf("failed to save image: %w", err)
+	}
+
+	return img, nil
+}
+
+// Delete отвязывает картинку от товара и удаляет ее файлы из хранилища.
+func (is *ImageService) Delete(ctx context.Context, merchID, imageID int) error {
+	img, err := is.MerchRepo.DeleteImage(ctx, merchID, imageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	is.removeFiles(ctx, img.Key, img.ThumbnailKey)
+	return nil
+}
+
+// MaxSize - максимальный размер загружаемой картинки в байтах.
+func (is *ImageService) MaxSize() int64 {
+	if is.config == nil || is.config.Images.MaxSize <= 0 {
+		return defaultMaxImageSize
+	}
+	return int64(is.config.Images.MaxSize) << 10
+}
+
+func (is *ImageService) thumbnailSize() int {
+	if is.config == nil || is.config.Images.ThumbnailSize <= 0 {
+		return defaultThumbnailSize
+	}
+	return is.config.Images.ThumbnailSize
+}
+
+// removeFiles удаляет файлы из хранилища. Ошибки только логируются:
+// запись в БД уже удалена или не создана, а лишний файл ничего не ломает.
+func (is *ImageService) removeFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := is.Storage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			is.logger.WarnContext(ctx, "Failed to delete file from storage", "key", key, "error", err)
+		}
+	}
+}
+
+// makeThumbnail уменьшает картинку так, чтобы она вписалась в квадрат size x size.
+// Маленькие картинки не увеличиваются. JPEG остается JPEG, остальное сохраняется в PNG ради прозрачности.
+func makeThumbnail(src image.Image, format string, size int) ([]byte, string, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+func extension(contentType string) string {
+	if contentType == "image/jpeg" {
+		return "jpeg"
+	}
+	return "png"
+}
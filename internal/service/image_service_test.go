@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"EmployeeMerchStore/config"
+	"EmployeeMerchStore/internal/logging"
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memStorage - хранилище в памяти вместо диска или S3.
+type memStorage struct {
+	files map[string][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{files: make(map[string][]byte)}
+}
+
+func (s *memStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.files[key] = data
+	return nil
+}
+
+func (s *memStorage) Delete(ctx context.Context, key string) error {
+	if _, ok := s.files[key]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memStorage) URL(key string) string {
+	return "https://cdn.example.com/" + key
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, x%h, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadImage_Success(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	store := newMemStorage()
+	imageService := NewImageService(mockRepo, store, &config.Config{Images: config.ImagesConfig{ThumbnailSize: 64}}, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6, Name: "hoody"}, nil).Once()
+	mockRepo.On("AddImage", mock.Anything, mock.AnythingOfType("models.MerchImage")).Return(3, nil).Once()
+
+	img, err := imageService.Upload(context.Background(), 6, bytes.NewReader(testPNG(t, 400, 200)))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, img.ID)
+	assert.Equal(t, 400, img.Width)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.True(t, strings.HasPrefix(img.URL, "https://cdn.example.com/merch/6/"))
+	assert.Len(t, store.files, 2)
+
+	// Миниатюра вписана в 64x64 с сохранением пропорций
+	thumb, _, err := image.DecodeConfig(bytes.NewReader(store.files[img.ThumbnailKey]))
+	assert.NoError(t, err)
+	assert.Equal(t, 64, thumb.Width)
+	assert.Equal(t, 32, thumb.Height)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUploadImage_NotAnImage(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	store := newMemStorage()
+	imageService := NewImageService(mockRepo, store, &config.Config{}, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6}, nil).Once()
+
+	_, err := imageService.Upload(context.Background(), 6, strings.NewReader("definitely not a picture"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+	assert.Empty(t, store.files)
+}
+
+func TestUploadImage_TooLarge(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	store := newMemStorage()
+	imageService := NewImageService(mockRepo, store, &config.Config{Images: config.ImagesConfig{MaxSize: 1}}, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6}, nil).Once()
+
+	_, err := imageService.Upload(context.Background(), 6, bytes.NewReader(make([]byte, 2048)))
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+	assert.Empty(t, store.files)
+}
+
+func TestUploadImage_RepoErrorRemovesFiles(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	store := newMemStorage()
+	imageService := NewImageService(mockRepo, store, &config.Config{}, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6}, nil).Once()
+	mockRepo.On("AddImage", mock.Anything, mock.Anything).Return(0, assert.AnError).Once()
+
+	_, err := imageService.Upload(context.Background(), 6, bytes.NewReader(testPNG(t, 10, 10)))
+	assert.Error(t, err)
+	assert.Empty(t, store.files)
+}
+
+func TestDeleteImage_RemovesFiles(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	store := newMemStorage()
+	store.files["merch/6/a.png"] = []byte("a")
+	store.files["merch/6/a_thumb.png"] = []byte("a")
+	imageService := NewImageService(mockRepo, store, &config.Config{}, logging.Discard())
+
+	mockRepo.On("DeleteImage", mock.Anything, 6, 3).
+		Return(models.MerchImage{ID: 3, MerchID: 6, Key: "merch/6/a.png", ThumbnailKey: "merch/6/a_thumb.png"}, nil).Once()
+
+	assert.NoError(t, imageService.Delete(context.Background(), 6, 3))
+	assert.Empty(t, store.files)
+}
+
+func TestDeleteImage_NotFound(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	imageService := NewImageService(mockRepo, newMemStorage(), &config.Config{}, logging.Discard())
+
+	mockRepo.On("DeleteImage", mock.Anything, 6, 3).Return(models.MerchImage{}, repository.ErrImageNotFound).Once()
+
+	assert.ErrorIs(t, imageService.Delete(context.Background(), 6, 3), repository.ErrImageNotFound)
+}
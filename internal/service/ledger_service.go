@@ -3,24 +3,43 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
+	"EmployeeMerchStore/config"
 	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/tracing"
 	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/metrics"
+)
+
+const (
+	defaultEscrowTTL      = 72 * time.Hour
+	defaultExpireInterval = time.Minute
+	expireBatchSize       = 100
 )
 
 type LedgerService struct {
     LedgerRepo repository.LedgerRepositoryInterface
     UserRepo   repository.UserRepositoryInterface
+    config     *config.Config
+    logger     *slog.Logger
 }
 
-func NewLedgerService(ledgerRepo repository.LedgerRepositoryInterface, userRepo repository.UserRepositoryInterface) *LedgerService {
+func NewLedgerService(ledgerRepo repository.LedgerRepositoryInterface, userRepo repository.UserRepositoryInterface, config *config.Config, logger *slog.Logger) *LedgerService {
     return &LedgerService{
         LedgerRepo: ledgerRepo,
         UserRepo:   userRepo,
+        config:     config,
+        logger:     logger,
     }
 }
 
 func (ls *LedgerService) SendMoney(ctx context.Context, fromUserId, toUser string, amount int) error {
+    ctx, span := tracing.Start(ctx, "LedgerService.SendMoney")
+    defer span.End()
+
     if amount <= 0 {
         return fmt.Errorf("amount must be positive")
     }
@@ -44,11 +63,35 @@ func (ls *LedgerService) SendMoney(ctx context.Context, fromUserId, toUser strin
     if err := ls.LedgerRepo.SendMoney(ctx, fromUserId, toUserID, amount); err != nil {
         return fmt.Errorf("failed to send money: %w", err)
     }
+    metrics.CoinsTransferred.WithLabelValues("direct").Add(float64(amount))
+
+    return nil
+}
+
+// GrantCoins начисляет монеты пользователю, например премию за проект.
+func (ls *LedgerService) GrantCoins(ctx context.Context, username string, amount int) error {
+    ctx, span := tracing.Start(ctx, "LedgerService.GrantCoins")
+    defer span.End()
+
+    if amount <= 0 {
+        return fmt.Errorf("amount must be positive")
+    }
 
+    userID, _, err := ls.UserRepo.GetUserCredentials(ctx, username)
+    if err != nil {
+        return fmt.Errorf("failed to get user id for username '%s': %w", username, err)
+    }
+
+    if err := ls.LedgerRepo.GrantCoins(ctx, userID, amount); err != nil {
+        return fmt.Errorf("failed to grant coins: %w", err)
+    }
     return nil
 }
 
 func (ls *LedgerService) GetUserTransactions(ctx context.Context, id string) ([]*models.Ledger, []*models.Ledger, error) {
+    ctx, span := tracing.Start(ctx, "LedgerService.GetUserTransactions")
+    defer span.End()
+
     transactionsAll, err := ls.LedgerRepo.GetUserTransactions(ctx, id, 100, 0) // пример: limit 100, offset 0
     if err != nil {
         return nil, nil, fmt.Errorf("failed to get user transactions: %w", err)
@@ -65,12 +108,159 @@ func (ls *LedgerService) GetUserTransactions(ctx context.Context, id string) ([]
         // Затираем владельца ledger, вписываем получателся/отправителя
         transaction.UserID = transaction.Reference_id_usr
         
-        if transaction.MovementType == "transfer_in" {
+        switch transaction.MovementType {
+        case models.MovementTransferIn, models.MovementEscrowIn, models.MovementEscrowRelease, models.MovementReversalIn:
             transactionsIn = append(transactionsIn, &transaction)
-        } else if transaction.MovementType == "transfer_out" {
+        case models.MovementTransferOut, models.MovementEscrowHold, models.MovementReversalOut:
             transactionsOut = append(transactionsOut, &transaction)
         }
     }
 
     return transactionsIn, transactionsOut, nil
 }
+
+// SendMoneyEscrow создает отложенный перевод: монеты списываются у отправителя сразу,
+// но попадут к получателю только после того, как он примет перевод.
+func (ls *LedgerService) SendMoneyEscrow(ctx context.Context, fromUserId, toUser string, amount int) (int, error) {
+    ctx, span := tracing.Start(ctx, "LedgerService.SendMoneyEscrow")
+    defer span.End()
+
+    if amount <= 0 {
+        return 0, fmt.Errorf("amount must be positive")
+    }
+
+    toUserID, _, err := ls.UserRepo.GetUserCredentials(ctx, toUser)
+    if err != nil {
+        return 0, fmt.Errorf("failed to get recipient id for username '%s': %w", toUser, err)
+    }
+    if toUserID == "" {
+        return 0, fmt.Errorf("recipient not found")
+    }
+    if toUserID == fromUserId {
+        return 0, fmt.Errorf("cannot send coins to yourself")
+    }
+
+    transferID, err := ls.LedgerRepo.CreatePendingTransfer(ctx, fromUserId, toUserID, amount, ls.escrowTTL())
+    if err != nil {
+        return 0, fmt.Errorf("failed to create pending transfer: %w", err)
+    }
+    metrics.CoinsTransferred.WithLabelValues("escrow").Add(float64(amount))
+
+    return transferID, nil
+}
+
+// AcceptTransfer зачисляет получателю монеты отложенного перевода.
+func (ls *LedgerService) AcceptTransfer(ctx context.Context, userID string, transferID int) error {
+    ctx, span := tracing.Start(ctx, "LedgerService.AcceptTransfer")
+    defer span.End()
+
+    if err := ls.LedgerRepo.ResolvePendingTransfer(ctx, transferID, userID, models.PendingTransferAccepted); err != nil {
+        return fmt.Errorf("failed to accept transfer: %w", err)
+    }
+    return nil
+}
+
+// DeclineTransfer отклоняет отложенный перевод, монеты возвращаются отправителю.
+func (ls *LedgerService) DeclineTransfer(ctx context.Context, userID string, transferID int) error {
+    ctx, span := tracing.Start(ctx, "LedgerService.DeclineTransfer")
+    defer span.End()
+
+    if err := ls.LedgerRepo.ResolvePendingTransfer(ctx, transferID, userID, models.PendingTransferDeclined); err != nil {
+        return fmt.Errorf("failed to decline transfer: %w", err)
+    }
+    return nil
+}
+
+// CancelTransfer отменяет отправленный, но еще не принятый перевод.
+func (ls *LedgerService) CancelTransfer(ctx context.Context, userID string, transferID int) error {
+    ctx, span := tracing.Start(ctx, "LedgerService.CancelTransfer")
+    defer span.End()
+
+    if err := ls.LedgerRepo.ResolvePendingTransfer(ctx, transferID, userID, models.PendingTransferCancelled); err != nil {
+        return fmt.Errorf("failed to cancel transfer: %w", err)
+    }
+    return nil
+}
+
+func (ls *LedgerService) GetPendingTransfers(ctx context.Context, userID string) ([]models.PendingTransfer, error) {
+    ctx, span := tracing.Start(ctx, "LedgerService.GetPendingTransfers")
+    defer span.End()
+
+    transfers, err := ls.LedgerRepo.GetPendingTransfers(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get pending transfers: %w", err)
+    }
+    return transfers, nil
+}
+
+// ReverseTransfer откатывает ошибочный перевод от имени администратора.
+// allowNegative подтверждает откат, даже если получатель уже потратил монеты.
+func (ls *LedgerService) ReverseTransfer(ctx context.Context, adminID string, transferID int, reason string, allowNegative bool) (models.TransferReversal, error) {
+    ctx, span := tracing.Start(ctx, "LedgerService.ReverseTransfer")
+    defer span.End()
+
+    if strings.TrimSpace(reason) == "" {
+        return models.TransferReversal{}, fmt.Errorf("reversal reason is required")
+    }
+
+    reversal, err := ls.LedgerRepo.ReverseTransfer(ctx, transferID, adminID, reason, allowNegative)
+    if err != nil {
+        return models.TransferReversal{}, fmt.Errorf("failed to reverse transfer %d: %w", transferID, err)
+    }
+
+    return reversal, nil
+}
+
+// ExpirePendingTransfers возвращает отправителям все просроченные переводы.
+func (ls *LedgerService) ExpirePendingTransfers(ctx context.Context) (int, error) {
+    ctx, span := tracing.Start(ctx, "LedgerService.ExpirePendingTransfers")
+    defer span.End()
+
+    total := 0
+    for {
+        n, err := ls.LedgerRepo.ExpirePendingTransfers(ctx, expireBatchSize)
+        if err != nil {
+            return total, fmt.Errorf("failed to expire pending transfers: %w", err)
+        }
+        total += n
+        if n < expireBatchSize {
+            return total, nil
+        }
+    }
+}
+
+// RunPendingTransfersExpirer периодически возвращает просроченные переводы, пока не отменят ctx.
+func (ls *LedgerService) RunPendingTransfersExpirer(ctx context.Context) {
+    ticker := time.NewTicker(ls.expireInterval())
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            n, err := ls.ExpirePendingTransfers(ctx)
+            if err != nil {
+                ls.logger.ErrorContext(ctx, "Pending transfers expiration failed", "error", err)
+                continue
+            }
+            if n > 0 {
+                ls.logger.InfoContext(ctx, "Expired pending transfers", "count", n)
+            }
+        }
+    }
+}
+
+func (ls *LedgerService) escrowTTL() time.Duration {
+    if ls.config == nil || ls.config.Transfers.EscrowTTL <= 0 {
+        return defaultEscrowTTL
+    }
+    return time.Duration(ls.config.Transfers.EscrowTTL) * time.Minute
+}
+
+func (ls *LedgerService) expireInterval() time.Duration {
+    if ls.config == nil || ls.config.Transfers.ExpireInterval <= 0 {
+        return defaultExpireInterval
+    }
+    return time.Duration(ls.config.Transfers.ExpireInterval) * time.Second
+}
@@ -4,8 +4,12 @@ import (
     "context"
     "errors"
     "testing"
+    "time"
 
+    "EmployeeMerchStore/config"
+    "EmployeeMerchStore/internal/logging"
     "EmployeeMerchStore/internal/models"
+    "EmployeeMerchStore/internal/repository"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
 )
@@ -27,10 +31,43 @@ func (m *MockLedgerRepo) GetUserTransactions(ctx context.Context, id string, lim
 	return args.Get(0).(*[]models.Ledger), args.Error(1)
 }
 
+func (m *MockLedgerRepo) CreatePendingTransfer(ctx context.Context, fromUser, toUser string, amount int, ttl time.Duration) (int, error) {
+	args := m.Called(ctx, fromUser, toUser, amount, ttl)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockLedgerRepo) ResolvePendingTransfer(ctx context.Context, id int, userID, status string) error {
+	args := m.Called(ctx, id, userID, status)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepo) ExpirePendingTransfers(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockLedgerRepo) GetPendingTransfers(ctx context.Context, userID string) ([]models.PendingTransfer, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PendingTransfer), args.Error(1)
+}
+
+func (m *MockLedgerRepo) ReverseTransfer(ctx context.Context, transferID int, adminID, reason string, allowNegative bool) (models.TransferReversal, error) {
+	args := m.Called(ctx, transferID, adminID, reason, allowNegative)
+	return args.Get(0).(models.TransferReversal), args.Error(1)
+}
+
+func (m *MockLedgerRepo) GrantCoins(ctx context.Context, userID string, amount int) error {
+	args := m.Called(ctx, userID, amount)
+	return args.Error(0)
+}
+
 func TestSendMoney_Success(t *testing.T) {
     mockLedgerRepo := new(MockLedgerRepo)
     mockUserRepo := new(MockUserRepo)
-    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
 
     mockUserRepo.On("GetUserCredentials", mock.Anything, "recipient").
         Return("user-id-2", "some-pass", nil).Once()
@@ -51,7 +88,7 @@ func TestSendMoney_Success(t *testing.T) {
 func TestSendMoney_InsufficientFunds(t *testing.T) {
     mockLedgerRepo := new(MockLedgerRepo)
     mockUserRepo := new(MockUserRepo)
-    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
 
     mockUserRepo.On("GetUserCredentials", mock.Anything, "recipient").
         Return("recipientID", "some-pass", nil).Once()
@@ -72,7 +109,7 @@ func TestSendMoney_InsufficientFunds(t *testing.T) {
 func TestSendMoney_InvalidAmount(t *testing.T) {
     mockLedgerRepo := new(MockLedgerRepo)
     mockUserRepo := new(MockUserRepo)
-    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
 
     err := ledgerService.SendMoney(context.Background(), "sender", "recipient", -10)
     assert.Error(t, err)
@@ -83,7 +120,7 @@ func TestSendMoney_InvalidAmount(t *testing.T) {
 func TestSendMoney_RecipientNotFound(t *testing.T) {
     mockLedgerRepo := new(MockLedgerRepo)
     mockUserRepo := new(MockUserRepo)
-    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
 
     mockUserRepo.On("GetUserCredentials", mock.Anything, "recipient").
         Return("", "", errors.New("user not found")).Once()
@@ -104,7 +141,7 @@ func TestSendMoney_RecipientNotFound(t *testing.T) {
 
 func TestGetUserTransactions_Success(t *testing.T) {
     mockLedgerRepo := new(MockLedgerRepo)
-    ledgerService := NewLedgerService(mockLedgerRepo, nil)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
 
     transactions := []models.Ledger{
         {ID: 1, MovementType: "transfer_in"},
@@ -125,7 +162,7 @@ func TestGetUserTransactions_Success(t *testing.T) {
 
 func TestGetUserTransactions_Error(t *testing.T) {
     mockLedgerRepo := new(MockLedgerRepo)
-    ledgerService := NewLedgerService(mockLedgerRepo, nil)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
 
     mockLedgerRepo.On("GetUserTransactions", mock.Anything, "user-id", 100, 0).
         Return(nil, errors.New("DB error")).Once()
@@ -137,3 +174,196 @@ func TestGetUserTransactions_Error(t *testing.T) {
 
     mockLedgerRepo.AssertExpectations(t)
 }
+
+func TestSendMoneyEscrow_UsesConfiguredTTL(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    mockUserRepo := new(MockUserRepo)
+    cfg := &config.Config{Transfers: config.TransfersConfig{EscrowTTL: 30}}
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, cfg, logging.Discard())
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "recipient").
+        Return("user-id-2", "some-pass", nil).Once()
+    mockLedgerRepo.On("CreatePendingTransfer", mock.Anything, "sender", "user-id-2", 50, 30*time.Minute).
+        Return(7, nil).Once()
+
+    transferID, err := ledgerService.SendMoneyEscrow(context.Background(), "sender", "recipient", 50)
+    assert.NoError(t, err)
+    assert.Equal(t, 7, transferID)
+
+    mockUserRepo.AssertExpectations(t)
+    mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestSendMoneyEscrow_DefaultTTL(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    mockUserRepo := new(MockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "recipient").
+        Return("user-id-2", "some-pass", nil).Once()
+    mockLedgerRepo.On("CreatePendingTransfer", mock.Anything, "sender", "user-id-2", 50, defaultEscrowTTL).
+        Return(1, nil).Once()
+
+    _, err := ledgerService.SendMoneyEscrow(context.Background(), "sender", "recipient", 50)
+    assert.NoError(t, err)
+
+    mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestSendMoneyEscrow_ToYourself(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    mockUserRepo := new(MockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "me").
+        Return("my-id", "some-pass", nil).Once()
+
+    _, err := ledgerService.SendMoneyEscrow(context.Background(), "my-id", "me", 50)
+    assert.Error(t, err)
+
+    mockLedgerRepo.AssertNotCalled(t, "CreatePendingTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendMoneyEscrow_InsufficientFunds(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    mockUserRepo := new(MockUserRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "recipient").
+        Return("user-id-2", "some-pass", nil).Once()
+    mockLedgerRepo.On("CreatePendingTransfer", mock.Anything, "sender", "user-id-2", 5000, mock.Anything).
+        Return(0, repository.ErrInsufficientFunds).Once()
+
+    _, err := ledgerService.SendMoneyEscrow(context.Background(), "sender", "recipient", 5000)
+    assert.ErrorIs(t, err, repository.ErrInsufficientFunds)
+}
+
+func TestResolveTransfer_Statuses(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    mockLedgerRepo.On("ResolvePendingTransfer", mock.Anything, 1, "recipient-id", models.PendingTransferAccepted).Return(nil).Once()
+    mockLedgerRepo.On("ResolvePendingTransfer", mock.Anything, 2, "recipient-id", models.PendingTransferDeclined).Return(nil).Once()
+    mockLedgerRepo.On("ResolvePendingTransfer", mock.Anything, 3, "sender-id", models.PendingTransferCancelled).
+        Return(repository.ErrPendingTransferClosed).Once()
+
+    assert.NoError(t, ledgerService.AcceptTransfer(context.Background(), "recipient-id", 1))
+    assert.NoError(t, ledgerService.DeclineTransfer(context.Background(), "recipient-id", 2))
+    assert.ErrorIs(t, ledgerService.CancelTransfer(context.Background(), "sender-id", 3), repository.ErrPendingTransferClosed)
+
+    mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestExpirePendingTransfers_Batches(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    mockLedgerRepo.On("ExpirePendingTransfers", mock.Anything, expireBatchSize).Return(expireBatchSize, nil).Once()
+    mockLedgerRepo.On("ExpirePendingTransfers", mock.Anything, expireBatchSize).Return(3, nil).Once()
+
+    n, err := ledgerService.ExpirePendingTransfers(context.Background())
+    assert.NoError(t, err)
+    assert.Equal(t, expireBatchSize+3, n)
+
+    mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestGetUserTransactions_IncludesEscrow(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    transactions := []models.Ledger{
+        {ID: 1, MovementType: models.MovementEscrowIn},
+        {ID: 2, MovementType: models.MovementEscrowHold},
+        {ID: 3, MovementType: models.MovementEscrowRelease},
+    }
+
+    mockLedgerRepo.On("GetUserTransactions", mock.Anything, "user-id", 100, 0).
+        Return(&transactions, nil).Once()
+
+    inTx, outTx, err := ledgerService.GetUserTransactions(context.Background(), "user-id")
+    assert.NoError(t, err)
+    assert.Len(t, inTx, 2)
+    assert.Len(t, outTx, 1)
+}
+
+func TestGetUserTransactions_EscrowReleaseIsIncoming(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    // Отправитель: монеты заморожены, затем перевод отклонен и монеты вернулись
+    transactions := []models.Ledger{
+        {ID: 1, Amount: 300, MovementType: models.MovementEscrowHold, Reference_id_usr: "recipient-id"},
+        {ID: 2, Amount: 300, MovementType: models.MovementEscrowRelease, Reference_id_usr: "recipient-id"},
+    }
+
+    mockLedgerRepo.On("GetUserTransactions", mock.Anything, "sender-id", 100, 0).
+        Return(&transactions, nil).Once()
+
+    inTx, outTx, err := ledgerService.GetUserTransactions(context.Background(), "sender-id")
+    assert.NoError(t, err)
+    assert.Len(t, outTx, 1)
+    if assert.Len(t, inTx, 1) {
+        assert.Equal(t, models.MovementEscrowRelease, inTx[0].MovementType)
+        assert.Equal(t, float64(300), inTx[0].Amount)
+        assert.Equal(t, "recipient-id", inTx[0].UserID)
+    }
+}
+
+func TestReverseTransfer_Success(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    expected := models.TransferReversal{ID: 1, TransferID: 42, Amount: 500, ReversedBy: "admin-id", Reason: "wrong colleague"}
+    mockLedgerRepo.On("ReverseTransfer", mock.Anything, 42, "admin-id", "wrong colleague", false).
+        Return(expected, nil).Once()
+
+    reversal, err := ledgerService.ReverseTransfer(context.Background(), "admin-id", 42, "wrong colleague", false)
+    assert.NoError(t, err)
+    assert.Equal(t, expected, reversal)
+
+    mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestReverseTransfer_ReasonRequired(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    _, err := ledgerService.ReverseTransfer(context.Background(), "admin-id", 42, "  ", false)
+    assert.Error(t, err)
+
+    mockLedgerRepo.AssertNotCalled(t, "ReverseTransfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReverseTransfer_FundsSpent(t *testing.T) {
+    mockLedgerRepo := new(MockLedgerRepo)
+    ledgerService := NewLedgerService(mockLedgerRepo, nil, &config.Config{}, logging.Discard())
+
+    mockLedgerRepo.On("ReverseTransfer", mock.Anything, 42, "admin-id", "wrong colleague", false).
+        Return(models.TransferReversal{}, repository.ErrRecipientFundsSpent).Once()
+
+    _, err := ledgerService.ReverseTransfer(context.Background(), "admin-id", 42, "wrong colleague", false)
+    assert.ErrorIs(t, err, repository.ErrRecipientFundsSpent)
+}
+
+func TestGrantCoins_Success(t *testing.T) {
+	mockLedgerRepo := new(MockLedgerRepo)
+	mockUserRepo := new(MockUserRepo)
+	ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
+
+	mockUserRepo.On("GetUserCredentials", mock.Anything, "alice").Return("user-id-1", "hash", nil).Once()
+	mockLedgerRepo.On("GrantCoins", mock.Anything, "user-id-1", 300).Return(nil).Once()
+
+	assert.NoError(t, ledgerService.GrantCoins(context.Background(), "alice", 300))
+	mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestGrantCoins_InvalidAmount(t *testing.T) {
+	mockLedgerRepo := new(MockLedgerRepo)
+	mockUserRepo := new(MockUserRepo)
+	ledgerService := NewLedgerService(mockLedgerRepo, mockUserRepo, &config.Config{}, logging.Discard())
+
+	assert.Error(t, ledgerService.GrantCoins(context.Background(), "alice", 0))
+	mockUserRepo.AssertNotCalled(t, "GetUserCredentials", mock.Anything, mock.Anything)
+	mockLedgerRepo.AssertNotCalled(t, "GrantCoins", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/tracing"
+)
+
+const defaultScheduleInterval = time.Minute
+
+type MerchService struct {
+	MerchRepo    repository.MerchRepositoryInterface
+	WishlistRepo repository.WishlistRepositoryInterface
+	logger       *slog.Logger
+}
+
+func NewMerchService(merchRepo repository.MerchRepositoryInterface, wishlistRepo repository.WishlistRepositoryInterface, logger *slog.Logger) *MerchService {
+	return &MerchService{
+		MerchRepo:    merchRepo,
+		WishlistRepo: wishlistRepo,
+		logger:       logger,
+	}
+}
+
+// ListMerch возвращает каталог вместе с вариантами товаров.
+func (ms *MerchService) ListMerch(ctx context.Context, filter models.MerchFilter) ([]models.Merch, error) {
+	filter.Tag = normalizeTag(filter.Tag)
+	merchList, err := ms.MerchRepo.ListMerch(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch: %w", err)
+	}
+	if len(merchList) == 0 {
+		return merchList, nil
+	}
+
+	// Варианты и картинки грузим только для попавших в выдачу товаров
+	ids := make([]int, len(merchList))
+	for i := range merchList {
+		ids[i] = merchList[i].ID
+	}
+
+	variants, err := ms.MerchRepo.ListVariants(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch variants: %w", err)
+	}
+
+	images, err := ms.MerchRepo.ListImages(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merch images: %w", err)
+	}
+
+	byMerch := make(map[int][]models.MerchVariant)
+	for _, v := range variants {
+		byMerch[v.MerchID] = append(byMerch[v.MerchID], v)
+	}
+	imagesByMerch := make(map[int][]models.MerchImage)
+	for _, img := range images {
+		imagesByMerch[img.MerchID] = append(imagesByMerch[img.MerchID], img)
+	}
+	for i := range merchList {
+		merchList[i].Variants = byMerch[merchList[i].ID]
+		merchList[i].Images = imagesByMerch[merchList[i].ID]
+	}
+
+	return merchList, nil
+}
+
+func (ms *MerchService) GetMerch(ctx context.Context, id int) (models.Merch, error) {
+	merch, err := ms.MerchRepo.GetMerch(ctx, id)
+	if err != nil {
+		return models.Merch{}, fmt.Errorf("failed to get merch %d: %w", id, err)
+	}
+
+	merch.Variants, err = ms.MerchRepo.GetVariants(ctx, id)
+	if err != nil {
+		return models.Merch{}, fmt.Errorf("failed to get variants of merch %d: %w", id, err)
+	}
+
+	merch.Images, err = ms.MerchRepo.GetImages(ctx, id)
+	if err != nil {
+		return models.Merch{}, fmt.Errorf("failed to get images of merch %d: %w", id, err)
+	}
+
+	return merch, nil
+}
+
+func (ms *MerchService) CreateMerch(ctx context.Context, name string, price int, description string, stock *int) (int, error) {
+	if err := validateMerch(name, price); err != nil {
+		return 0, err
+	}
+	if stock != nil && *stock < 0 {
+		return 0, fmt.Errorf("stock must not be negative")
+	}
+
+	id, err := ms.MerchRepo.CreateMerch(ctx, name, price, description, stock)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create merch: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateMerch обновляет товар. При снижении цены уведомляет всех, у кого он в вишлисте.
+func (ms *MerchService) UpdateMerch(ctx context.Context, id int, name string, price int, description string) error {
+	if err := validateMerch(name, price); err != nil {
+		return err
+	}
+
+	before, err := ms.MerchRepo.GetMerch(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get merch %d: %w", id, err)
+	}
+
+	if err := ms.MerchRepo.UpdateMerch(ctx, id, name, price, description); err != nil {
+		return fmt.Errorf("failed to update merch %d: %w", id, err)
+	}
+
+	if price < before.Price {
+		ms.notifyWishlisters(ctx, id, models.NotificationPriceDrop,
+			fmt.Sprintf("Price of %s dropped from %d to %d", name, before.Price, price))
+	}
+	return nil
+}
+
+// ArchiveMerch убирает товар из каталога и продажи, не трогая историю покупок.
+func (ms *MerchService) ArchiveMerch(ctx context.Context, id int) error {
+	if err := ms.MerchRepo.ArchiveMerch(ctx, id); err != nil {
+		return fmt.Errorf("failed to archive merch %d: %w", id, err)
+	}
+	return nil
+}
+
+func (ms *MerchService) RestoreMerch(ctx context.Context, id int) error {
+	if err := ms.MerchRepo.RestoreMerch(ctx, id); err != nil {
+		return fmt.Errorf("failed to restore merch %d: %w", id, err)
+	}
+	return nil
+}
+
+// Restock пополняет склад и возвращает новый остаток.
+func (ms *MerchService) Restock(ctx context.Context, id, quantity int) (int, error) {
+	if quantity <= 0 {
+		return 0, fmt.Errorf("restock quantity must be positive")
+	}
+
+	before, err := ms.MerchRepo.GetMerch(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get merch %d: %w", id, err)
+	}
+
+	stock, err := ms.MerchRepo.Restock(ctx, id, quantity)
+	if err != nil {
+		return 0, fmt.Errorf("failed to restock merch %d: %w", id, err)
+	}
+
+	if isSoldOut(before.Stock) {
+		ms.notifyRestock(ctx, before)
+	}
+	return stock, nil
+}
+
+// SetStock выставляет точный остаток, nil делает запас неограниченным.
+func (ms *MerchService) SetStock(ctx context.Context, id int, stock *int) error {
+	if stock != nil && *stock < 0 {
+		return fmt.Errorf("stock must not be negative")
+	}
+
+	before, err := ms.MerchRepo.GetMerch(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get merch %d: %w", id, err)
+	}
+
+	if err := ms.MerchRepo.SetStock(ctx, id, stock); err != nil {
+		return fmt.Errorf("failed to set stock for merch %d: %w", id, err)
+	}
+
+	if isSoldOut(before.Stock) && !isSoldOut(stock) {
+		ms.notifyRestock(ctx, before)
+	}
+	return nil
+}
+
+// SetPurchaseLimit ограничивает, сколько штук товара один сотрудник может купить за период.
+// nil снимает ограничение, пустой период означает "за все время".
+func (ms *MerchService) SetPurchaseLimit(ctx context.Context, id int, limit *int, period string) error {
+	if limit != nil && *limit <= 0 {
+		return fmt.Errorf("purchase limit must be positive")
+	}
+	if period == "" {
+		period = models.LimitPeriodLifetime
+	}
+	if !models.IsLimitPeriod(period) {
+		return fmt.Errorf("unknown limit period %q", period)
+	}
+
+	if err := ms.MerchRepo.SetPurchaseLimit(ctx, id, limit, period); err != nil {
+		return fmt.Errorf("failed to set purchase limit for merch %d: %w", id, err)
+	}
+	return nil
+}
+
+// SetAvailability задает окно продажи товара. nil с любой стороны - без ограничения.
+func (ms *MerchService) SetAvailability(ctx context.Context, id int, from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return fmt.Errorf("available_until must be after available_from")
+	}
+
+	if err := ms.MerchRepo.SetAvailability(ctx, id, from, until); err != nil {
+		return fmt.Errorf("failed to set availability of merch %d: %w", id, err)
+	}
+	return nil
+}
+
+// SchedulePrice планирует смену цены товара на момент effectiveAt.
+func (ms *MerchService) SchedulePrice(ctx context.Context, merchID, price int, effectiveAt time.Time) (int, error) {
+	if price <= 0 {
+		return 0, fmt.Errorf("merch price must be positive")
+	}
+	if !effectiveAt.After(time.Now()) {
+		return 0, fmt.Errorf("effective_at must be in the future")
+	}
+
+	// Проверяем, что товар существует, чтобы не получить ошибку внешнего ключа
+	if _, err := ms.MerchRepo.GetMerch(ctx, merchID); err != nil {
+		return 0, fmt.Errorf("failed to get merch %d: %w", merchID, err)
+	}
+
+	id, err := ms.MerchRepo.SchedulePrice(ctx, models.ScheduledPrice{MerchID: merchID, Price: price, EffectiveAt: effectiveAt})
+	if err != nil {
+		return 0, fmt.Errorf("failed to schedule price for merch %d: %w", merchID, err)
+	}
+	return id, nil
+}
+
+func (ms *MerchService) GetScheduledPrices(ctx context.Context, merchID int) ([]models.ScheduledPrice, error) {
+	changes, err := ms.MerchRepo.GetScheduledPrices(ctx, merchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled prices of merch %d: %w", merchID, err)
+	}
+	return changes, nil
+}
+
+func (ms *MerchService) CancelScheduledPrice(ctx context.Context, merchID, id int) error {
+	if err := ms.MerchRepo.CancelScheduledPrice(ctx, merchID, id); err != nil {
+		return fmt.Errorf("failed to cancel scheduled price %d: %w", id, err)
+	}
+	return nil
+}
+
+// ApplyScheduledPrices применяет наступившие смены цены и, как и UpdateMerch,
+// уведомляет вишлисты о снижении. Возвращает число измененных товаров.
+func (ms *MerchService) ApplyScheduledPrices(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "MerchService.ApplyScheduledPrices")
+	defer span.End()
+
+	applied, err := ms.MerchRepo.ApplyDuePrices(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply scheduled prices: %w", err)
+	}
+
+	for _, change := range applied {
+		if change.NewPrice < change.OldPrice {
+			ms.notifyWishlisters(ctx, change.MerchID, models.NotificationPriceDrop,
+				fmt.Sprintf("Price of %s dropped from %d to %d", change.Name, change.OldPrice, change.NewPrice))
+		}
+	}
+	return len(applied), nil
+}
+
+// RunScheduledPrices периодически применяет запланированные цены, пока не отменят ctx.
+func (ms *MerchService) RunScheduledPrices(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultScheduleInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := ms.ApplyScheduledPrices(ctx)
+			if err != nil {
+				ms.logger.ErrorContext(ctx, "Scheduled prices failed", "error", err)
+				continue
+			}
+			if n > 0 {
+				ms.logger.InfoContext(ctx, "Applied scheduled prices", "count", n)
+			}
+		}
+	}
+}
+
+// CreateVariant добавляет мерчу вариант (размер, цвет) со своим SKU, надбавкой и остатком.
+func (ms *MerchService) CreateVariant(ctx context.Context, variant models.MerchVariant) (int, error) {
+	merch, err := ms.MerchRepo.GetMerch(ctx, variant.MerchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get merch %d: %w", variant.MerchID, err)
+	}
+	if err := validateVariant(variant, merch.Price); err != nil {
+		return 0, err
+	}
+	if variant.Stock != nil && *variant.Stock < 0 {
+		return 0, fmt.Errorf("stock must not be negative")
+	}
+
+	id, err := ms.MerchRepo.CreateVariant(ctx, variant)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create variant: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateVariant меняет SKU, размер, цвет и надбавку варианта.
+func (ms *MerchService) UpdateVariant(ctx context.Context, variant models.MerchVariant) error {
+	merch, err := ms.MerchRepo.GetMerch(ctx, variant.MerchID)
+	if err != nil {
+		return fmt.Errorf("failed to get merch %d: %w", variant.MerchID, err)
+	}
+	if err := validateVariant(variant, merch.Price); err != nil {
+		return err
+	}
+
+	if err := ms.MerchRepo.UpdateVariant(ctx, variant); err != nil {
+		return fmt.Errorf("failed to update variant %d: %w", variant.ID, err)
+	}
+	return nil
+}
+
+func (ms *MerchService) GetVariants(ctx context.Context, merchID int) ([]models.MerchVariant, error) {
+	variants, err := ms.MerchRepo.GetVariants(ctx, merchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants of merch %d: %w", merchID, err)
+	}
+	return variants, nil
+}
+
+// RestockVariant пополняет склад варианта и возвращает новый остаток.
+// Вишлистеров уведомляет, только если до пополнения были распроданы все варианты товара.
+func (ms *MerchService) RestockVariant(ctx context.Context, merchID, id, quantity int) (int, error) {
+	if quantity <= 0 {
+		return 0, fmt.Errorf("restock quantity must be positive")
+	}
+
+	merch, err := ms.MerchRepo.GetMerch(ctx, merchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get merch %d: %w", merchID, err)
+	}
+	variants, err := ms.MerchRepo.GetVariants(ctx, merchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get variants of merch %d: %w", merchID, err)
+	}
+	var before *models.MerchVariant
+	for i := range variants {
+		if variants[i].ID == id {
+			before = &variants[i]
+		}
+	}
+	if before == nil {
+		return 0, fmt.Errorf("failed to restock variant %d: %w", id, repository.ErrVariantNotFound)
+	}
+
+	stock, err := ms.MerchRepo.RestockVariant(ctx, id, quantity)
+	if err != nil {
+		return 0, fmt.Errorf("failed to restock variant %d: %w", id, err)
+	}
+
+	if allVariantsSoldOut(variants) {
+		ms.notifyRestock(ctx, merch)
+	}
+	return stock, nil
+}
+
+func (ms *MerchService) notifyRestock(ctx context.Context, merch models.Merch) {
+	ms.notifyWishlisters(ctx, merch.ID, models.NotificationRestock,
+		fmt.Sprintf("%s is back in stock", merch.Name))
+}
+
+// notifyWishlisters не прерывает операцию: товар уже обновлен, уведомление - best effort.
+func (ms *MerchService) notifyWishlisters(ctx context.Context, merchID int, kind, message string) {
+	if ms.WishlistRepo == nil {
+		return
+	}
+	if _, err := ms.WishlistRepo.NotifyWishlisters(ctx, merchID, kind, message); err != nil {
+		ms.logger.WarnContext(ctx, "Failed to notify wishlisters", "merch_id", merchID, "error", err)
+	}
+}
+
+// isSoldOut - запас ограничен и закончился.
+func isSoldOut(stock *int) bool {
+	return stock != nil && *stock == 0
+}
+
+// allVariantsSoldOut сообщает, что товар с вариантами закончился целиком.
+func allVariantsSoldOut(variants []models.MerchVariant) bool {
+	for i := range variants {
+		if !isSoldOut(variants[i].Stock) {
+			return false
+		}
+	}
+	return len(variants) > 0
+}
+
+func validateVariant(variant models.MerchVariant, merchPrice int) error {
+	if strings.TrimSpace(variant.SKU) == "" {
+		return fmt.Errorf("variant sku is required")
+	}
+	if variant.Size != "" && !isVariantSize(variant.Size) {
+		return fmt.Errorf("unknown size %q, expected one of %s", variant.Size, strings.Join(models.VariantSizes, ", "))
+	}
+	if merchPrice+variant.PriceDelta <= 0 {
+		return fmt.Errorf("variant price must be positive")
+	}
+	return nil
+}
+
+func isVariantSize(size string) bool {
+	for _, s := range models.VariantSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMerch(name string, price int) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("merch name is required")
+	}
+	if price <= 0 {
+		return fmt.Errorf("merch price must be positive")
+	}
+	return nil
+}
+
+const maxTagLength = 50
+
+// SetCategory переносит товар в категорию. Пустая категория убирает товар из категории.
+func (ms *MerchService) SetCategory(ctx context.Context, id int, category string) error {
+	if err := ms.MerchRepo.SetCategory(ctx, id, strings.TrimSpace(category)); err != nil {
+		return fmt.Errorf("failed to set category of merch %d: %w", id, err)
+	}
+	return nil
+}
+
+// SetTags заменяет теги товара. Теги приводятся к нижнему регистру, дубликаты убираются.
+func (ms *MerchService) SetTags(ctx context.Context, id int, tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len([]rune(tag)) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is too long: max %d characters", tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if err := ms.MerchRepo.SetTags(ctx, id, normalized); err != nil {
+		return nil, fmt.Errorf("failed to set tags of merch %d: %w", id, err)
+	}
+	return normalized, nil
+}
+
+func (ms *MerchService) ListCategories(ctx context.Context) ([]models.Category, error) {
+	categories, err := ms.MerchRepo.ListCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (ms *MerchService) CreateCategory(ctx context.Context, slug, name string) (int, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" || strings.TrimSpace(name) == "" {
+		return 0, fmt.Errorf("slug and name are required")
+	}
+
+	id, err := ms.MerchRepo.CreateCategory(ctx, slug, strings.TrimSpace(name))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create category: %w", err)
+	}
+	return id, nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
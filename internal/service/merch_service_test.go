@@ -0,0 +1,540 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"EmployeeMerchStore/internal/logging"
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMerchRepo struct {
+	mock.Mock
+}
+
+func (m *MockMerchRepo) GetMerch(ctx context.Context, id int) (models.Merch, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Merch), args.Error(1)
+}
+
+func (m *MockMerchRepo) ListMerch(ctx context.Context, filter models.MerchFilter) ([]models.Merch, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Merch), args.Error(1)
+}
+
+func (m *MockMerchRepo) SetPurchaseLimit(ctx context.Context, id int, limit *int, period string) error {
+	args := m.Called(ctx, id, limit, period)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) SetAvailability(ctx context.Context, id int, from, until *time.Time) error {
+	args := m.Called(ctx, id, from, until)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) SchedulePrice(ctx context.Context, change models.ScheduledPrice) (int, error) {
+	args := m.Called(ctx, change)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepo) GetScheduledPrices(ctx context.Context, merchID int) ([]models.ScheduledPrice, error) {
+	args := m.Called(ctx, merchID)
+	return args.Get(0).([]models.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockMerchRepo) CancelScheduledPrice(ctx context.Context, merchID, id int) error {
+	args := m.Called(ctx, merchID, id)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) ApplyDuePrices(ctx context.Context) ([]models.AppliedPriceChange, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.AppliedPriceChange), args.Error(1)
+}
+
+func (m *MockMerchRepo) SetCategory(ctx context.Context, id int, category string) error {
+	args := m.Called(ctx, id, category)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) SetTags(ctx context.Context, id int, tags []string) error {
+	args := m.Called(ctx, id, tags)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) ListCategories(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *MockMerchRepo) CreateCategory(ctx context.Context, slug, name string) (int, error) {
+	args := m.Called(ctx, slug, name)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepo) AddImage(ctx context.Context, image models.MerchImage) (int, error) {
+	args := m.Called(ctx, image)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepo) ListImages(ctx context.Context, merchIDs []int) ([]models.MerchImage, error) {
+	args := m.Called(ctx, merchIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MerchImage), args.Error(1)
+}
+
+func (m *MockMerchRepo) GetImages(ctx context.Context, merchID int) ([]models.MerchImage, error) {
+	args := m.Called(ctx, merchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MerchImage), args.Error(1)
+}
+
+func (m *MockMerchRepo) DeleteImage(ctx context.Context, merchID, id int) (models.MerchImage, error) {
+	args := m.Called(ctx, merchID, id)
+	return args.Get(0).(models.MerchImage), args.Error(1)
+}
+
+func (m *MockMerchRepo) CreateMerch(ctx context.Context, name string, price int, description string, stock *int) (int, error) {
+	args := m.Called(ctx, name, price, description, stock)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepo) UpdateMerch(ctx context.Context, id int, name string, price int, description string) error {
+	args := m.Called(ctx, id, name, price, description)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) ArchiveMerch(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) RestoreMerch(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) Restock(ctx context.Context, id, quantity int) (int, error) {
+	args := m.Called(ctx, id, quantity)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepo) SetStock(ctx context.Context, id int, stock *int) error {
+	args := m.Called(ctx, id, stock)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) ListVariants(ctx context.Context, merchIDs []int) ([]models.MerchVariant, error) {
+	args := m.Called(ctx, merchIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MerchVariant), args.Error(1)
+}
+
+func (m *MockMerchRepo) GetVariants(ctx context.Context, merchID int) ([]models.MerchVariant, error) {
+	args := m.Called(ctx, merchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MerchVariant), args.Error(1)
+}
+
+func (m *MockMerchRepo) CreateVariant(ctx context.Context, variant models.MerchVariant) (int, error) {
+	args := m.Called(ctx, variant)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMerchRepo) UpdateVariant(ctx context.Context, variant models.MerchVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func (m *MockMerchRepo) RestockVariant(ctx context.Context, id, quantity int) (int, error) {
+	args := m.Called(ctx, id, quantity)
+	return args.Int(0), args.Error(1)
+}
+
+func TestListMerch_Success(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	stock := 3
+	expected := []models.Merch{
+		{ID: 1, Name: "T-Shirt", Price: 80},
+		{ID: 10, Name: "pink-hoody", Price: 500, Stock: &stock},
+	}
+	variants := []models.MerchVariant{
+		{ID: 1, MerchID: 10, SKU: "PH-M", Size: "M", Price: 500},
+		{ID: 2, MerchID: 10, SKU: "PH-XL", Size: "XL", PriceDelta: 20, Price: 520},
+	}
+	mockRepo.On("ListMerch", mock.Anything, models.MerchFilter{}).Return(expected, nil).Once()
+	mockRepo.On("ListVariants", mock.Anything, []int{1, 10}).Return(variants, nil).Once()
+	mockRepo.On("ListImages", mock.Anything, []int{1, 10}).Return([]models.MerchImage{{ID: 1, MerchID: 1, URL: "/media/merch/1/a.png"}}, nil).Once()
+
+	merchList, err := merchService.ListMerch(context.Background(), models.MerchFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, merchList, 2)
+	assert.Empty(t, merchList[0].Variants)
+	assert.Equal(t, variants, merchList[1].Variants)
+	assert.Len(t, merchList[0].Images, 1)
+	assert.Empty(t, merchList[1].Images)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListMerch_EmptySkipsVariantsAndImages(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("ListMerch", mock.Anything, models.MerchFilter{Category: "none"}).Return(nil, nil).Once()
+
+	merchList, err := merchService.ListMerch(context.Background(), models.MerchFilter{Category: "none"})
+	assert.NoError(t, err)
+	assert.Empty(t, merchList)
+
+	mockRepo.AssertNotCalled(t, "ListVariants", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "ListImages", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMerch_Validation(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	negative := -1
+	_, err := merchService.CreateMerch(context.Background(), "", 10, "", nil)
+	assert.Error(t, err)
+	_, err = merchService.CreateMerch(context.Background(), "cap", 0, "", nil)
+	assert.Error(t, err)
+	_, err = merchService.CreateMerch(context.Background(), "cap", 10, "", &negative)
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "CreateMerch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestock_Success(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 10).Return(models.Merch{ID: 10, Name: "pink-hoody", Price: 500}, nil).Once()
+	mockRepo.On("Restock", mock.Anything, 10, 5).Return(5, nil).Once()
+
+	stock, err := merchService.Restock(context.Background(), 10, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stock)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestock_InvalidQuantity(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	_, err := merchService.Restock(context.Background(), 10, 0)
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "Restock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestock_NotFound(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 99).Return(models.Merch{}, repository.ErrMerchNotFound).Once()
+
+	_, err := merchService.Restock(context.Background(), 99, 1)
+	assert.True(t, errors.Is(err, repository.ErrMerchNotFound))
+}
+
+func TestCreateVariant_Success(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	variant := models.MerchVariant{MerchID: 6, SKU: "HOODY-XXL-BLACK", Size: "XXL", Colour: "black", PriceDelta: 50}
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6, Name: "hoody", Price: 300}, nil).Once()
+	mockRepo.On("CreateVariant", mock.Anything, variant).Return(3, nil).Once()
+
+	id, err := merchService.CreateVariant(context.Background(), variant)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, id)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateVariant_Validation(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6, Name: "hoody", Price: 300}, nil)
+
+	cases := []models.MerchVariant{
+		{MerchID: 6, SKU: "", Size: "M"},
+		{MerchID: 6, SKU: "HOODY-XXXL", Size: "XXXL"},
+		{MerchID: 6, SKU: "HOODY-FREE", PriceDelta: -300},
+	}
+	for _, variant := range cases {
+		_, err := merchService.CreateVariant(context.Background(), variant)
+		assert.Error(t, err, variant.SKU)
+	}
+
+	mockRepo.AssertNotCalled(t, "CreateVariant", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMerch_PriceDropNotifiesWishlisters(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	mockWishlistRepo := new(MockWishlistRepo)
+	merchService := NewMerchService(mockRepo, mockWishlistRepo, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 10).Return(models.Merch{ID: 10, Name: "pink-hoody", Price: 500}, nil).Once()
+	mockRepo.On("UpdateMerch", mock.Anything, 10, "pink-hoody", 400, "A pink hoody").Return(nil).Once()
+	mockWishlistRepo.On("NotifyWishlisters", mock.Anything, 10, models.NotificationPriceDrop, "Price of pink-hoody dropped from 500 to 400").
+		Return(2, nil).Once()
+
+	err := merchService.UpdateMerch(context.Background(), 10, "pink-hoody", 400, "A pink hoody")
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockWishlistRepo.AssertExpectations(t)
+}
+
+func TestUpdateMerch_PriceRiseDoesNotNotify(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	mockWishlistRepo := new(MockWishlistRepo)
+	merchService := NewMerchService(mockRepo, mockWishlistRepo, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 10).Return(models.Merch{ID: 10, Name: "pink-hoody", Price: 500}, nil).Once()
+	mockRepo.On("UpdateMerch", mock.Anything, 10, "pink-hoody", 600, "").Return(nil).Once()
+
+	err := merchService.UpdateMerch(context.Background(), 10, "pink-hoody", 600, "")
+	assert.NoError(t, err)
+
+	mockWishlistRepo.AssertNotCalled(t, "NotifyWishlisters", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestock_SoldOutNotifiesWishlisters(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	mockWishlistRepo := new(MockWishlistRepo)
+	merchService := NewMerchService(mockRepo, mockWishlistRepo, logging.Discard())
+
+	soldOut := 0
+	mockRepo.On("GetMerch", mock.Anything, 10).Return(models.Merch{ID: 10, Name: "pink-hoody", Price: 500, Stock: &soldOut}, nil).Once()
+	mockRepo.On("Restock", mock.Anything, 10, 3).Return(3, nil).Once()
+	mockWishlistRepo.On("NotifyWishlisters", mock.Anything, 10, models.NotificationRestock, "pink-hoody is back in stock").
+		Return(1, nil).Once()
+
+	_, err := merchService.Restock(context.Background(), 10, 3)
+	assert.NoError(t, err)
+
+	mockWishlistRepo.AssertExpectations(t)
+}
+
+func TestRestockVariant_SoldOutNotifiesWishlisters(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	mockWishlistRepo := new(MockWishlistRepo)
+	merchService := NewMerchService(mockRepo, mockWishlistRepo, logging.Discard())
+
+	soldOut := 0
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6, Name: "hoody", Price: 300}, nil).Once()
+	mockRepo.On("GetVariants", mock.Anything, 6).Return([]models.MerchVariant{{ID: 4, MerchID: 6, SKU: "HOODY-M", Stock: &soldOut}}, nil).Once()
+	mockRepo.On("RestockVariant", mock.Anything, 4, 10).Return(10, nil).Once()
+	mockWishlistRepo.On("NotifyWishlisters", mock.Anything, 6, models.NotificationRestock, "hoody is back in stock").
+		Return(1, nil).Once()
+
+	stock, err := merchService.RestockVariant(context.Background(), 6, 4, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stock)
+
+	mockRepo.AssertExpectations(t)
+	mockWishlistRepo.AssertExpectations(t)
+}
+
+// Один вариант распродан, но другой есть на складе: товар не заканчивался, уведомлять не о чем.
+func TestRestockVariant_OtherVariantInStockDoesNotNotify(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	mockWishlistRepo := new(MockWishlistRepo)
+	merchService := NewMerchService(mockRepo, mockWishlistRepo, logging.Discard())
+
+	soldOut, inStock := 0, 3
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6, Name: "hoody", Price: 300}, nil).Once()
+	mockRepo.On("GetVariants", mock.Anything, 6).Return([]models.MerchVariant{
+		{ID: 4, MerchID: 6, SKU: "HOODY-M", Stock: &soldOut},
+		{ID: 5, MerchID: 6, SKU: "HOODY-L", Stock: &inStock},
+	}, nil).Once()
+	mockRepo.On("RestockVariant", mock.Anything, 4, 10).Return(10, nil).Once()
+
+	stock, err := merchService.RestockVariant(context.Background(), 6, 4, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stock)
+
+	mockRepo.AssertExpectations(t)
+	mockWishlistRepo.AssertNotCalled(t, "NotifyWishlisters", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestockVariant_WrongMerch(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("GetMerch", mock.Anything, 6).Return(models.Merch{ID: 6, Name: "hoody", Price: 300}, nil).Once()
+	mockRepo.On("GetVariants", mock.Anything, 6).Return(nil, nil).Once()
+
+	_, err := merchService.RestockVariant(context.Background(), 6, 4, 10)
+	assert.ErrorIs(t, err, repository.ErrVariantNotFound)
+
+	mockRepo.AssertNotCalled(t, "RestockVariant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListMerch_FilterByCategoryAndTag(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("ListMerch", mock.Anything, models.MerchFilter{Category: "apparel", Tag: "winter"}).
+		Return([]models.Merch{{ID: 6, Name: "hoody", Category: "apparel", Tags: []string{"winter"}}}, nil).Once()
+	mockRepo.On("ListVariants", mock.Anything, []int{6}).Return(nil, nil).Once()
+	mockRepo.On("ListImages", mock.Anything, []int{6}).Return(nil, nil).Once()
+
+	merchList, err := merchService.ListMerch(context.Background(), models.MerchFilter{Category: "apparel", Tag: " Winter "})
+	assert.NoError(t, err)
+	assert.Len(t, merchList, 1)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetTags_Normalizes(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("SetTags", mock.Anything, 6, []string{"winter", "warm"}).Return(nil).Once()
+
+	tags, err := merchService.SetTags(context.Background(), 6, []string{" Winter", "warm", "WINTER", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"winter", "warm"}, tags)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetCategory_NotFound(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("SetCategory", mock.Anything, 6, "food").Return(repository.ErrCategoryNotFound).Once()
+
+	err := merchService.SetCategory(context.Background(), 6, "food")
+	assert.ErrorIs(t, err, repository.ErrCategoryNotFound)
+}
+
+func TestSetPurchaseLimit_DefaultsToLifetime(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	limit := 1
+	mockRepo.On("SetPurchaseLimit", mock.Anything, 10, &limit, models.LimitPeriodLifetime).Return(nil).Once()
+
+	assert.NoError(t, merchService.SetPurchaseLimit(context.Background(), 10, &limit, ""))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetPurchaseLimit_Validation(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	zero := 0
+	three := 3
+	assert.Error(t, merchService.SetPurchaseLimit(context.Background(), 10, &zero, models.LimitPeriodQuarter))
+	assert.Error(t, merchService.SetPurchaseLimit(context.Background(), 10, &three, "week"))
+
+	mockRepo.AssertNotCalled(t, "SetPurchaseLimit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetAvailability_Validation(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	until := from.Add(-time.Hour)
+	assert.Error(t, merchService.SetAvailability(context.Background(), 10, &from, &until))
+
+	mockRepo.AssertNotCalled(t, "SetAvailability", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSchedulePrice_Validation(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	_, err := merchService.SchedulePrice(context.Background(), 10, 0, time.Now().Add(time.Hour))
+	assert.Error(t, err)
+	_, err = merchService.SchedulePrice(context.Background(), 10, 400, time.Now().Add(-time.Hour))
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "SchedulePrice", mock.Anything, mock.Anything)
+}
+
+func TestSchedulePrice_Success(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	at := time.Now().Add(24 * time.Hour)
+	mockRepo.On("GetMerch", mock.Anything, 10).Return(models.Merch{ID: 10, Name: "pink-hoody", Price: 500}, nil).Once()
+	mockRepo.On("SchedulePrice", mock.Anything, models.ScheduledPrice{MerchID: 10, Price: 400, EffectiveAt: at}).Return(7, nil).Once()
+
+	id, err := merchService.SchedulePrice(context.Background(), 10, 400, at)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApplyScheduledPrices_NotifiesOnlyOnDrop(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	mockWishlistRepo := new(MockWishlistRepo)
+	merchService := NewMerchService(mockRepo, mockWishlistRepo, logging.Discard())
+
+	mockRepo.On("ApplyDuePrices", mock.Anything).Return([]models.AppliedPriceChange{
+		{MerchID: 10, Name: "pink-hoody", OldPrice: 500, NewPrice: 400},
+		{MerchID: 11, Name: "cup", OldPrice: 20, NewPrice: 30},
+	}, nil).Once()
+	mockWishlistRepo.On("NotifyWishlisters", mock.Anything, 10, models.NotificationPriceDrop, "Price of pink-hoody dropped from 500 to 400").
+		Return(1, nil).Once()
+
+	n, err := merchService.ApplyScheduledPrices(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	mockWishlistRepo.AssertExpectations(t)
+	mockWishlistRepo.AssertNumberOfCalls(t, "NotifyWishlisters", 1)
+}
+
+func TestArchiveMerch_NotFound(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("ArchiveMerch", mock.Anything, 99).Return(repository.ErrMerchNotFound).Once()
+
+	err := merchService.ArchiveMerch(context.Background(), 99)
+	assert.ErrorIs(t, err, repository.ErrMerchNotFound)
+}
+
+func TestRestoreMerch_NameTaken(t *testing.T) {
+	mockRepo := new(MockMerchRepo)
+	merchService := NewMerchService(mockRepo, nil, logging.Discard())
+
+	mockRepo.On("RestoreMerch", mock.Anything, 10).Return(repository.ErrMerchNameTaken).Once()
+
+	err := merchService.RestoreMerch(context.Background(), 10)
+	assert.ErrorIs(t, err, repository.ErrMerchNameTaken)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"EmployeeMerchStore/config"
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+)
+
+type OrderService struct {
+	OrderRepo repository.OrderRepositoryInterface
+	config    *config.Config
+}
+
+func NewOrderService(orderRepo repository.OrderRepositoryInterface, config *config.Config) *OrderService {
+	return &OrderService{
+		OrderRepo: orderRepo,
+		config:    config,
+	}
+}
+
+// GetUserOrders возвращает заказы сотрудника. С openOnly - только те, что еще не выданы.
+func (os *OrderService) GetUserOrders(ctx context.Context, userID string, openOnly bool) ([]models.Order, error) {
+	orders, err := os.OrderRepo.GetUserOrders(ctx, userID, openOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (os *OrderService) ListOrders(ctx context.Context, status string) ([]models.Order, error) {
+	if status != "" && !models.IsOrderStatus(status) {
+		return nil, fmt.Errorf("unknown order status %q", status)
+	}
+
+	orders, err := os.OrderRepo.ListOrders(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, nil
+}
+
+// UpdateOrderStatus переводит заказ в новый статус. Отмена возвращает монеты плательщику.
+func (os *OrderService) UpdateOrderStatus(ctx context.Context, id int, status string) (models.Order, error) {
+	if !models.IsOrderStatus(status) {
+		return models.Order{}, fmt.Errorf("unknown order status %q", status)
+	}
+
+	order, err := os.OrderRepo.UpdateOrderStatus(ctx, id, status)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("failed to update order status: %w", err)
+	}
+	return order, nil
+}
+
+// Offices возвращает офисы, в которых можно забрать заказ.
+func (os *OrderService) Offices() []string {
+	return offices(os.config)
+}
+
+func offices(cfg *config.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	return cfg.Orders.Offices
+}
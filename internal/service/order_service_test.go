@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"EmployeeMerchStore/config"
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOrderRepo struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepo) GetUserOrders(ctx context.Context, userID string, openOnly bool) ([]models.Order, error) {
+	args := m.Called(ctx, userID, openOnly)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepo) ListOrders(ctx context.Context, status string) ([]models.Order, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Order), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockOrderRepo) UpdateOrderStatus(ctx context.Context, id int, status string) (models.Order, error) {
+	args := m.Called(ctx, id, status)
+	return args.Get(0).(models.Order), args.Error(1)
+}
+
+func TestUpdateOrderStatus_Success(t *testing.T) {
+	mockRepo := new(MockOrderRepo)
+	orderService := NewOrderService(mockRepo, &config.Config{})
+
+	mockRepo.On("UpdateOrderStatus", mock.Anything, 1, models.OrderPacked).
+		Return(models.Order{ID: 1, Status: models.OrderPacked}, nil).Once()
+
+	order, err := orderService.UpdateOrderStatus(context.Background(), 1, models.OrderPacked)
+	assert.NoError(t, err)
+	assert.Equal(t, models.OrderPacked, order.Status)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateOrderStatus_UnknownStatus(t *testing.T) {
+	mockRepo := new(MockOrderRepo)
+	orderService := NewOrderService(mockRepo, &config.Config{})
+
+	_, err := orderService.UpdateOrderStatus(context.Background(), 1, "lost")
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateOrderStatus_InvalidTransition(t *testing.T) {
+	mockRepo := new(MockOrderRepo)
+	orderService := NewOrderService(mockRepo, &config.Config{})
+
+	mockRepo.On("UpdateOrderStatus", mock.Anything, 1, models.OrderPlaced).
+		Return(models.Order{}, repository.ErrInvalidOrderTransition).Once()
+
+	_, err := orderService.UpdateOrderStatus(context.Background(), 1, models.OrderPlaced)
+	assert.ErrorIs(t, err, repository.ErrInvalidOrderTransition)
+}
+
+func TestCanTransitionOrder(t *testing.T) {
+	assert.True(t, models.CanTransitionOrder(models.OrderPlaced, models.OrderPacked))
+	assert.True(t, models.CanTransitionOrder(models.OrderReadyForPickup, models.OrderDelivered))
+	assert.True(t, models.CanTransitionOrder(models.OrderPacked, models.OrderCancelled))
+	assert.False(t, models.CanTransitionOrder(models.OrderPlaced, models.OrderDelivered))
+	assert.False(t, models.CanTransitionOrder(models.OrderDelivered, models.OrderCancelled))
+	assert.False(t, models.CanTransitionOrder(models.OrderCancelled, models.OrderPlaced))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+)
+
+type PromotionService struct {
+	PromotionRepo repository.PromotionRepositoryInterface
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepositoryInterface) *PromotionService {
+	return &PromotionService{PromotionRepo: promotionRepo}
+}
+
+// ListPromotions возвращает акции. С activeOnly - только действующие сейчас.
+func (ps *PromotionService) ListPromotions(ctx context.Context, activeOnly bool) ([]models.Promotion, error) {
+	promotions, err := ps.PromotionRepo.ListPromotions(ctx, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+func (ps *PromotionService) CreatePromotion(ctx context.Context, promotion models.Promotion) (int, error) {
+	if strings.TrimSpace(promotion.Name) == "" {
+		return 0, fmt.Errorf("name is required")
+	}
+	if err := validateDiscount(promotion.DiscountType, promotion.DiscountValue); err != nil {
+		return 0, err
+	}
+	if promotion.MerchID != nil && promotion.Category != "" {
+		return 0, fmt.Errorf("promotion applies either to merch_id or to category, not both")
+	}
+	if promotion.StartsAt.IsZero() || promotion.EndsAt.IsZero() {
+		return 0, fmt.Errorf("starts_at and ends_at are required")
+	}
+	if !promotion.EndsAt.After(promotion.StartsAt) {
+		return 0, fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	id, err := ps.PromotionRepo.CreatePromotion(ctx, promotion)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return id, nil
+}
+
+func (ps *PromotionService) EndPromotion(ctx context.Context, id int) error {
+	if err := ps.PromotionRepo.EndPromotion(ctx, id); err != nil {
+		return fmt.Errorf("failed to end promotion: %w", err)
+	}
+	return nil
+}
+
+func (ps *PromotionService) ListPromoCodes(ctx context.Context) ([]models.PromoCode, error) {
+	codes, err := ps.PromotionRepo.ListPromoCodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promo codes: %w", err)
+	}
+	return codes, nil
+}
+
+func (ps *PromotionService) CreatePromoCode(ctx context.Context, code models.PromoCode) (int, error) {
+	code.Code = strings.TrimSpace(code.Code)
+	if code.Code == "" {
+		return 0, fmt.Errorf("code is required")
+	}
+	if err := validateDiscount(code.DiscountType, code.DiscountValue); err != nil {
+		return 0, err
+	}
+	if code.MaxUses != nil && *code.MaxUses <= 0 {
+		return 0, fmt.Errorf("max_uses must be positive")
+	}
+	if code.StartsAt != nil && code.EndsAt != nil && !code.EndsAt.After(*code.StartsAt) {
+		return 0, fmt.Errorf("ends_at must be after starts_at")
+	}
+
+	id, err := ps.PromotionRepo.CreatePromoCode(ctx, code)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create promo code: %w", err)
+	}
+	return id, nil
+}
+
+func (ps *PromotionService) DisablePromoCode(ctx context.Context, id int) error {
+	if err := ps.PromotionRepo.DisablePromoCode(ctx, id); err != nil {
+		return fmt.Errorf("failed to disable promo code: %w", err)
+	}
+	return nil
+}
+
+func validateDiscount(discountType string, value int) error {
+	switch discountType {
+	case models.DiscountPercent:
+		if value <= 0 || value > 100 {
+			return fmt.Errorf("percent discount must be between 1 and 100")
+		}
+	case models.DiscountFixed:
+		if value <= 0 {
+			return fmt.Errorf("fixed discount must be positive")
+		}
+	default:
+		return fmt.Errorf("discount_type must be %q or %q", models.DiscountPercent, models.DiscountFixed)
+	}
+	return nil
+}
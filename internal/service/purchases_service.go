@@ -3,24 +3,38 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/config"
+	"EmployeeMerchStore/internal/metrics"
 	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/tracing"
+)
+
+const (
+	defaultReturnWindow  = 14 * 24 * time.Hour
+	maxGiftMessageLength = 500
 )
 
 type PurchasesService struct {
 	PurchasesRepo repository.PurchasesRepositoryInterface
 	UserRepo repository.UserRepositoryInterface
+	config *config.Config
 }
 
-func NewPurchasesService(PurchasesRepo repository.PurchasesRepositoryInterface, UserRepo repository.UserRepositoryInterface) *PurchasesService {
+func NewPurchasesService(PurchasesRepo repository.PurchasesRepositoryInterface, UserRepo repository.UserRepositoryInterface, config *config.Config) *PurchasesService {
 	return &PurchasesService{
 		PurchasesRepo: PurchasesRepo,
 		UserRepo: UserRepo,
+		config: config,
 	}
 }
 
 func (ps *PurchasesService) GetUserMerch(ctx context.Context, id string) ([]*models.UserMerch, error) {
+	ctx, span := tracing.Start(ctx, "PurchasesService.GetUserMerch")
+	defer span.End()
+
 	merchList, err := ps.PurchasesRepo.GetUserMerch(ctx, id)
 
 	if err != nil {
@@ -30,24 +44,243 @@ func (ps *PurchasesService) GetUserMerch(ctx context.Context, id string) ([]*mod
 	return merchList, nil
 }
 
-func (ps *PurchasesService) BuyMerch(ctx context.Context, userId, nameMerch string) error {
-    merchID, price, err := ps.PurchasesRepo.GetMerchId(ctx, nameMerch)
+// BuyOptions - необязательные параметры покупки.
+type BuyOptions struct {
+    SKU    string // вариант мерча, обязателен, если у мерча есть варианты
+    Office    string // офис выдачи заказа, по умолчанию - первый из конфига
+    PromoCode string // необязательный промокод
+}
+
+// BuyMerch покупает мерч и возвращает id созданного заказа.
+func (ps *PurchasesService) BuyMerch(ctx context.Context, userId, nameMerch string, opts BuyOptions) (int, error) {
+    ctx, span := tracing.Start(ctx, "PurchasesService.BuyMerch")
+    defer span.End()
+
+    purchase, err := ps.preparePurchase(ctx, userId, nameMerch, opts)
     if err != nil {
-        return fmt.Errorf("failed to get merch id for '%s': %w", nameMerch, err)
+        return 0, err
     }
 
     balance, err := ps.UserRepo.GetBalance(ctx, userId)
     if err != nil {
-        return fmt.Errorf("failed to get balance: %w", err)
+        return 0, fmt.Errorf("failed to get balance: %w", err)
+    }
+
+    if purchase.UnitPrice > balance {
+        return 0, fmt.Errorf("not enough coins")
+    }
+
+    if err := ps.PurchasesRepo.BuyMerch(ctx, purchase); err != nil {
+        return 0, fmt.Errorf("failed to buy merch: %w", err)
+    }
+    metrics.Purchases.WithLabelValues(nameMerch).Inc()
+
+    return purchase.OrderID, nil
+}
+
+// GiftMerch покупает мерч за счет buyerId и дарит его пользователю toUser.
+// Возвращает id подарка.
+func (ps *PurchasesService) GiftMerch(ctx context.Context, buyerId, toUser, nameMerch, message string, opts BuyOptions) (int, error) {
+    ctx, span := tracing.Start(ctx, "PurchasesService.GiftMerch")
+    defer span.End()
+
+    if len([]rune(message)) > maxGiftMessageLength {
+        return 0, fmt.Errorf("gift message is too long: max %d characters", maxGiftMessageLength)
+    }
+
+    toUserID, _, err := ps.UserRepo.GetUserCredentials(ctx, toUser)
+    if err != nil {
+        return 0, fmt.Errorf("failed to get recipient id for username '%s': %w", toUser, err)
+    }
+    if toUserID == "" {
+        return 0, fmt.Errorf("recipient not found")
+    }
+    if toUserID == buyerId {
+        return 0, fmt.Errorf("cannot gift merch to yourself")
+    }
+
+    purchase, err := ps.preparePurchase(ctx, toUserID, nameMerch, opts)
+    if err != nil {
+        return 0, err
+    }
+
+    balance, err := ps.UserRepo.GetBalance(ctx, buyerId)
+    if err != nil {
+        return 0, fmt.Errorf("failed to get balance: %w", err)
+    }
+    if purchase.UnitPrice > balance {
+        return 0, fmt.Errorf("not enough coins")
+    }
+
+    giftID, err := ps.PurchasesRepo.GiftMerch(ctx, purchase, buyerId, message)
+    if err != nil {
+        return 0, fmt.Errorf("failed to gift merch: %w", err)
+    }
+    metrics.Purchases.WithLabelValues(nameMerch).Inc()
+
+    return giftID, nil
+}
+
+func (ps *PurchasesService) GetReceivedGifts(ctx context.Context, userId string) ([]models.Gift, error) {
+    ctx, span := tracing.Start(ctx, "PurchasesService.GetReceivedGifts")
+    defer span.End()
+
+    gifts, err := ps.PurchasesRepo.GetReceivedGifts(ctx, userId)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get received gifts: %w", err)
+    }
+    return gifts, nil
+}
+
+// GetPurchaseHistory возвращает каждую покупку пользователя отдельно, с ценой на момент покупки.
+func (ps *PurchasesService) GetPurchaseHistory(ctx context.Context, userId string) ([]models.PurchaseHistoryItem, error) {
+    ctx, span := tracing.Start(ctx, "PurchasesService.GetPurchaseHistory")
+    defer span.End()
+
+    history, err := ps.PurchasesRepo.GetPurchaseHistory(ctx, userId)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get purchase history: %w", err)
+    }
+    return history, nil
+}
+
+// preparePurchase собирает покупку одной единицы мерча для userId с учетом варианта.
+func (ps *PurchasesService) preparePurchase(ctx context.Context, userId, nameMerch string, opts BuyOptions) (*models.Purchase, error) {
+    office, err := ps.resolveOffice(opts.Office)
+    if err != nil {
+        return nil, err
+    }
+
+    merchID, price, err := ps.PurchasesRepo.GetMerchId(ctx, nameMerch)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get merch id for '%s': %w", nameMerch, err)
+    }
+
+    purchase := &models.Purchase{
+        UserID:    userId,
+        MerchID:   merchID,
+        Quantity:  1,
+        UnitPrice: price,
+        Office:    office,
+    }
+
+    variant, err := ps.resolveVariant(ctx, merchID, opts.SKU)
+    if err != nil {
+        return nil, err
+    }
+    if variant != nil {
+        purchase.VariantID = &variant.ID
+        purchase.UnitPrice = variant.Price
     }
 
-    if price > balance {
-    	return fmt.Errorf("not enoauth coins")
+    if err := ps.applyDiscounts(ctx, purchase, opts.PromoCode); err != nil {
+        return nil, err
     }
 
-    if err := ps.PurchasesRepo.BuyMerch(ctx, userId, merchID, 1, price); err != nil {
-        return fmt.Errorf("failed to buy merch: %w", err)
+    return purchase, nil
+}
+
+// applyDiscounts применяет к покупке самую выгодную действующую акцию,
+// а затем промокод к уже сниженной цене. Цена не опускается ниже нуля.
+func (ps *PurchasesService) applyDiscounts(ctx context.Context, purchase *models.Purchase, code string) error {
+    promotions, err := ps.PurchasesRepo.GetActivePromotions(ctx, purchase.MerchID)
+    if err != nil {
+        return fmt.Errorf("failed to get promotions: %w", err)
     }
 
+    price := purchase.UnitPrice
+    best := 0
+    for i := range promotions {
+        discount := models.DiscountAmount(promotions[i].DiscountType, promotions[i].DiscountValue, price)
+        if discount > best {
+            best = discount
+            purchase.PromotionID = &promotions[i].ID
+        }
+    }
+    price -= best
+
+    if code != "" {
+        promoCode, err := ps.PurchasesRepo.GetPromoCode(ctx, code)
+        if err != nil {
+            return fmt.Errorf("failed to get promo code: %w", err)
+        }
+        if !promoCode.Active(time.Now()) {
+            return repository.ErrPromoCodeInvalid
+        }
+        if promoCode.MerchID != nil && *promoCode.MerchID != purchase.MerchID {
+            return repository.ErrPromoCodeInvalid
+        }
+        price -= models.DiscountAmount(promoCode.DiscountType, promoCode.DiscountValue, price)
+        purchase.PromoCodeID = &promoCode.ID
+    }
+
+    purchase.Discount = purchase.UnitPrice - price
+    purchase.UnitPrice = price
     return nil
 }
+
+// resolveOffice проверяет офис выдачи. Пустой офис заменяется первым из конфига.
+// Если офисы не настроены, офис не указывается.
+func (ps *PurchasesService) resolveOffice(office string) (string, error) {
+    list := offices(ps.config)
+    if len(list) == 0 {
+        return "", nil
+    }
+    if office == "" {
+        return list[0], nil
+    }
+    for _, o := range list {
+        if o == office {
+            return o, nil
+        }
+    }
+    return "", fmt.Errorf("unknown office %q", office)
+}
+
+// resolveVariant находит вариант по SKU. Без SKU возвращает nil,
+// но только если у мерча нет вариантов.
+func (ps *PurchasesService) resolveVariant(ctx context.Context, merchID int, sku string) (*models.MerchVariant, error) {
+    if sku == "" {
+        count, err := ps.PurchasesRepo.CountVariants(ctx, merchID)
+        if err != nil {
+            return nil, fmt.Errorf("failed to check merch variants: %w", err)
+        }
+        if count > 0 {
+            return nil, repository.ErrVariantRequired
+        }
+        return nil, nil
+    }
+
+    variant, err := ps.PurchasesRepo.GetVariant(ctx, merchID, sku)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get variant '%s': %w", sku, err)
+    }
+    return &variant, nil
+}
+
+// ReturnMerch возвращает одну единицу мерча и возвращает сумму, зачисленную пользователю.
+// sku указывает вариант, если мерч покупался с вариантом.
+func (ps *PurchasesService) ReturnMerch(ctx context.Context, userId, nameMerch, sku string) (int, error) {
+	ctx, span := tracing.Start(ctx, "PurchasesService.ReturnMerch")
+	defer span.End()
+
+	// Товар ищем среди покупок пользователя: имя в каталоге мог занять новый товар
+	merchID, variantID, err := ps.PurchasesRepo.GetReturnableMerch(ctx, userId, nameMerch, sku)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find purchase of '%s': %w", nameMerch, err)
+	}
+
+	refund, err := ps.PurchasesRepo.ReturnMerch(ctx, userId, merchID, variantID, ps.returnWindow())
+	if err != nil {
+		return 0, fmt.Errorf("failed to return merch: %w", err)
+	}
+
+	return refund, nil
+}
+
+func (ps *PurchasesService) returnWindow() time.Duration {
+	if ps.config == nil || ps.config.Purchases.ReturnWindow <= 0 {
+		return defaultReturnWindow
+	}
+	return time.Duration(ps.config.Purchases.ReturnWindow) * 24 * time.Hour
+}
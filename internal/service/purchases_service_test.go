@@ -4,8 +4,11 @@ import (
     "context"
     "errors"
     "testing"
+    "time"
 
+    "EmployeeMerchStore/config"
     "EmployeeMerchStore/internal/models"
+    "EmployeeMerchStore/internal/repository"
 
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
@@ -32,15 +35,70 @@ func (m *MockPurchasesRepo) GetMerchId(ctx context.Context, name string) (int, i
     return args.Int(0), args.Int(1), args.Error(2)
 }
 
-func (m *MockPurchasesRepo) BuyMerch(ctx context.Context, userId string, merchId int, quantity, price int) error {
-    args := m.Called(ctx, userId, merchId, quantity, price)
+func (m *MockPurchasesRepo) GetReturnableMerch(ctx context.Context, userId, name, sku string) (int, *int, error) {
+    args := m.Called(ctx, userId, name, sku)
+    variantID, _ := args.Get(1).(*int)
+    return args.Int(0), variantID, args.Error(2)
+}
+
+func (m *MockPurchasesRepo) BuyMerch(ctx context.Context, purchase *models.Purchase) error {
+    args := m.Called(ctx, purchase)
     return args.Error(0)
 }
 
+func (m *MockPurchasesRepo) GetVariant(ctx context.Context, merchId int, sku string) (models.MerchVariant, error) {
+    args := m.Called(ctx, merchId, sku)
+    return args.Get(0).(models.MerchVariant), args.Error(1)
+}
+
+func (m *MockPurchasesRepo) CountVariants(ctx context.Context, merchId int) (int, error) {
+    args := m.Called(ctx, merchId)
+    return args.Int(0), args.Error(1)
+}
+
+func (m *MockPurchasesRepo) ReturnMerch(ctx context.Context, userId string, merchId int, variantId *int, window time.Duration) (int, error) {
+    args := m.Called(ctx, userId, merchId, variantId, window)
+    return args.Int(0), args.Error(1)
+}
+
+func (m *MockPurchasesRepo) GiftMerch(ctx context.Context, purchase *models.Purchase, buyerId, message string) (int, error) {
+    args := m.Called(ctx, purchase, buyerId, message)
+    return args.Int(0), args.Error(1)
+}
+
+func (m *MockPurchasesRepo) GetReceivedGifts(ctx context.Context, userId string) ([]models.Gift, error) {
+    args := m.Called(ctx, userId)
+    if args.Get(0) != nil {
+        return args.Get(0).([]models.Gift), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockPurchasesRepo) GetPurchaseHistory(ctx context.Context, userId string) ([]models.PurchaseHistoryItem, error) {
+    args := m.Called(ctx, userId)
+    if args.Get(0) != nil {
+        return args.Get(0).([]models.PurchaseHistoryItem), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockPurchasesRepo) GetActivePromotions(ctx context.Context, merchId int) ([]models.Promotion, error) {
+    args := m.Called(ctx, merchId)
+    if args.Get(0) != nil {
+        return args.Get(0).([]models.Promotion), args.Error(1)
+    }
+    return nil, args.Error(1)
+}
+
+func (m *MockPurchasesRepo) GetPromoCode(ctx context.Context, code string) (models.PromoCode, error) {
+    args := m.Called(ctx, code)
+    return args.Get(0).(models.PromoCode), args.Error(1)
+}
+
 func TestGetUserMerch_Success(t *testing.T) {
 	mockRepo := new(MockPurchasesRepo)
 	mockUserRepo := new(MockUserRepo)
-	purchasesService := NewPurchasesService(mockRepo, mockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
 
 	expectedMerch := []*models.UserMerch{
 		{MerchID: 1, Name: "T-Shirt", Quantity: 1},
@@ -59,7 +117,7 @@ func TestGetUserMerch_Success(t *testing.T) {
 func TestGetUserMerch_Error(t *testing.T) {
 	mockRepo := new(MockPurchasesRepo)
 	mockUserRepo := new(MockUserRepo)
-	purchasesService := NewPurchasesService(mockRepo, mockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
 
 	mockRepo.On("GetUserMerch", mock.Anything, "user-id").Return(nil, errors.New("DB error"))
 
@@ -73,13 +131,15 @@ func TestGetUserMerch_Error(t *testing.T) {
 func TestBuyMerch_Success(t *testing.T) {
     mockRepo := new(MockPurchasesRepo)
 	mockUserRepo := new(MockUserRepo)
-    purchasesService := NewPurchasesService(mockRepo, mockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
 
     mockRepo.On("GetMerchId", mock.Anything, "T-Shirt").Return(1, 1, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 1).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 1).Return(nil, nil).Once()
     mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(100, nil).Once()
-    mockRepo.On("BuyMerch", mock.Anything, "user-id", 1, 1, 1).Return(nil).Once()
+    mockRepo.On("BuyMerch", mock.Anything, &models.Purchase{UserID: "user-id", MerchID: 1, Quantity: 1, UnitPrice: 1}).Return(nil).Once()
 
-    err := purchasesService.BuyMerch(context.Background(), "user-id", "T-Shirt")
+    _, err := purchasesService.BuyMerch(context.Background(), "user-id", "T-Shirt", BuyOptions{})
     assert.NoError(t, err)
 
     mockRepo.AssertExpectations(t)
@@ -88,11 +148,11 @@ func TestBuyMerch_Success(t *testing.T) {
 func TestBuyMerch_GetMerchId_Error(t *testing.T) {
 	mockRepo := new(MockPurchasesRepo)
 	mockUserRepo := new(MockUserRepo)
-	purchasesService := NewPurchasesService(mockRepo, mockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
 
 	mockRepo.On("GetMerchId", mock.Anything, "T-Shirt").Return(0, 0, errors.New("not found"))
 
-	err := purchasesService.BuyMerch(context.Background(), "user-id", "T-Shirt")
+	_, err := purchasesService.BuyMerch(context.Background(), "user-id", "T-Shirt", BuyOptions{})
 	assert.Error(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -101,13 +161,295 @@ func TestBuyMerch_GetMerchId_Error(t *testing.T) {
 func TestBuyMerch_BuyMerch_Error(t *testing.T) {
 	mockRepo := new(MockPurchasesRepo)
 	mockUserRepo := new(MockUserRepo)
-	purchasesService := NewPurchasesService(mockRepo, mockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
 
 	mockRepo.On("GetMerchId", mock.Anything, "T-Shirt").Return(0, 0, errors.New("not found")).Once()
     mockUserRepo.AssertNotCalled(t, "GetBalance", mock.Anything, "user-id")
 
-	err := purchasesService.BuyMerch(context.Background(), "user-id", "T-Shirt")
+	_, err := purchasesService.BuyMerch(context.Background(), "user-id", "T-Shirt", BuyOptions{})
 	assert.Error(t, err)
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestReturnMerch_Success(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	cfg := &config.Config{Purchases: config.PurchasesConfig{ReturnWindow: 7}}
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, cfg)
+
+	mockRepo.On("GetReturnableMerch", mock.Anything, "user-id", "hoody", "").Return(6, nil, nil).Once()
+	mockRepo.On("ReturnMerch", mock.Anything, "user-id", 6, (*int)(nil), 7*24*time.Hour).Return(300, nil).Once()
+
+	refund, err := purchasesService.ReturnMerch(context.Background(), "user-id", "hoody", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 300, refund)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReturnMerch_WindowExpired(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+	mockRepo.On("GetReturnableMerch", mock.Anything, "user-id", "hoody", "").Return(6, nil, nil).Once()
+	mockRepo.On("ReturnMerch", mock.Anything, "user-id", 6, (*int)(nil), defaultReturnWindow).Return(0, repository.ErrReturnWindowExpired).Once()
+
+	_, err := purchasesService.ReturnMerch(context.Background(), "user-id", "hoody", "")
+	assert.ErrorIs(t, err, repository.ErrReturnWindowExpired)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// Архивный товар возвращается по покупке пользователя, даже если его имя занял новый товар.
+func TestReturnMerch_ArchivedItemWithReusedName(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+	variantID := 8
+	mockRepo.On("GetReturnableMerch", mock.Anything, "user-id", "hoody", "HOODY-M").Return(3, &variantID, nil).Once()
+	mockRepo.On("ReturnMerch", mock.Anything, "user-id", 3, &variantID, defaultReturnWindow).Return(300, nil).Once()
+
+	refund, err := purchasesService.ReturnMerch(context.Background(), "user-id", "hoody", "HOODY-M")
+	assert.NoError(t, err)
+	assert.Equal(t, 300, refund)
+
+	mockRepo.AssertNotCalled(t, "GetMerchId", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetVariant", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReturnMerch_NotOwned(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+	mockRepo.On("GetReturnableMerch", mock.Anything, "user-id", "hoody", "").Return(0, nil, repository.ErrPurchaseNotFound).Once()
+
+	_, err := purchasesService.ReturnMerch(context.Background(), "user-id", "hoody", "")
+	assert.ErrorIs(t, err, repository.ErrPurchaseNotFound)
+
+	mockRepo.AssertNotCalled(t, "ReturnMerch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuyMerch_OutOfStock(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+	mockRepo.On("GetMerchId", mock.Anything, "pink-hoody").Return(10, 500, nil).Once()
+	mockRepo.On("CountVariants", mock.Anything, 10).Return(0, nil).Once()
+	mockRepo.On("GetActivePromotions", mock.Anything, 10).Return(nil, nil).Once()
+	mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(1000, nil).Once()
+	mockRepo.On("BuyMerch", mock.Anything, mock.Anything).Return(repository.ErrOutOfStock).Once()
+
+	_, err := purchasesService.BuyMerch(context.Background(), "user-id", "pink-hoody", BuyOptions{})
+	assert.ErrorIs(t, err, repository.ErrOutOfStock)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBuyMerch_Variant(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+	variantID := 4
+	mockRepo.On("GetMerchId", mock.Anything, "hoody").Return(6, 300, nil).Once()
+	mockRepo.On("GetVariant", mock.Anything, 6, "HOODY-XXL").
+		Return(models.MerchVariant{ID: variantID, MerchID: 6, SKU: "HOODY-XXL", PriceDelta: 50, Price: 350}, nil).Once()
+	mockRepo.On("GetActivePromotions", mock.Anything, 6).Return(nil, nil).Once()
+	mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(1000, nil).Once()
+	mockRepo.On("BuyMerch", mock.Anything, &models.Purchase{UserID: "user-id", MerchID: 6, VariantID: &variantID, Quantity: 1, UnitPrice: 350}).
+		Return(nil).Once()
+
+	_, err := purchasesService.BuyMerch(context.Background(), "user-id", "hoody", BuyOptions{SKU: "HOODY-XXL"})
+	assert.NoError(t, err)
+
+	mockRepo.AssertNotCalled(t, "CountVariants", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBuyMerch_VariantRequired(t *testing.T) {
+	mockRepo := new(MockPurchasesRepo)
+	mockUserRepo := new(MockUserRepo)
+	purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+	mockRepo.On("GetMerchId", mock.Anything, "hoody").Return(6, 300, nil).Once()
+	mockRepo.On("CountVariants", mock.Anything, 6).Return(5, nil).Once()
+
+	_, err := purchasesService.BuyMerch(context.Background(), "user-id", "hoody", BuyOptions{})
+	assert.ErrorIs(t, err, repository.ErrVariantRequired)
+
+	mockRepo.AssertNotCalled(t, "BuyMerch", mock.Anything, mock.Anything)
+}
+
+func TestGiftMerch_Success(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "colleague").Return("colleague-id", "pass", nil).Once()
+    mockRepo.On("GetMerchId", mock.Anything, "cup").Return(2, 20, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 2).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 2).Return(nil, nil).Once()
+    mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(100, nil).Once()
+    mockRepo.On("GiftMerch", mock.Anything, &models.Purchase{UserID: "colleague-id", MerchID: 2, Quantity: 1, UnitPrice: 20}, "user-id", "Спасибо!").
+        Return(7, nil).Once()
+
+    giftID, err := purchasesService.GiftMerch(context.Background(), "user-id", "colleague", "cup", "Спасибо!", BuyOptions{})
+    assert.NoError(t, err)
+    assert.Equal(t, 7, giftID)
+
+    mockRepo.AssertExpectations(t)
+    mockUserRepo.AssertExpectations(t)
+}
+
+func TestGiftMerch_ToSelf(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "me").Return("user-id", "pass", nil).Once()
+
+    _, err := purchasesService.GiftMerch(context.Background(), "user-id", "me", "cup", "", BuyOptions{})
+    assert.Error(t, err)
+    mockRepo.AssertNotCalled(t, "GiftMerch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGiftMerch_NotEnoughCoins(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    mockUserRepo.On("GetUserCredentials", mock.Anything, "colleague").Return("colleague-id", "pass", nil).Once()
+    mockRepo.On("GetMerchId", mock.Anything, "hoody").Return(3, 300, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 3).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 3).Return(nil, nil).Once()
+    mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(100, nil).Once()
+
+    _, err := purchasesService.GiftMerch(context.Background(), "user-id", "colleague", "hoody", "", BuyOptions{})
+    assert.Error(t, err)
+    mockRepo.AssertNotCalled(t, "GiftMerch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBuyMerch_DefaultOffice(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    cfg := &config.Config{Orders: config.OrdersConfig{Offices: []string{"Москва", "Казань"}}}
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, cfg)
+
+    mockRepo.On("GetMerchId", mock.Anything, "cup").Return(2, 20, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 2).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 2).Return(nil, nil).Once()
+    mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(100, nil).Once()
+    mockRepo.On("BuyMerch", mock.Anything, &models.Purchase{UserID: "user-id", MerchID: 2, Quantity: 1, UnitPrice: 20, Office: "Москва"}).
+        Run(func(args mock.Arguments) { args.Get(1).(*models.Purchase).OrderID = 42 }).
+        Return(nil).Once()
+
+    orderID, err := purchasesService.BuyMerch(context.Background(), "user-id", "cup", BuyOptions{})
+    assert.NoError(t, err)
+    assert.Equal(t, 42, orderID)
+
+    mockRepo.AssertExpectations(t)
+}
+
+func TestBuyMerch_UnknownOffice(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    cfg := &config.Config{Orders: config.OrdersConfig{Offices: []string{"Москва"}}}
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, cfg)
+
+    _, err := purchasesService.BuyMerch(context.Background(), "user-id", "cup", BuyOptions{Office: "Лондон"})
+    assert.Error(t, err)
+    mockRepo.AssertNotCalled(t, "BuyMerch", mock.Anything, mock.Anything)
+}
+
+func TestGetPurchaseHistory_Success(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    // Две покупки одного мерча по разным ценам не склеиваются
+    history := []models.PurchaseHistoryItem{
+        {ID: 2, MerchID: 1, Name: "cup", Quantity: 1, UnitPrice: 25},
+        {ID: 1, MerchID: 1, Name: "cup", Quantity: 1, UnitPrice: 20, Returned: 1},
+    }
+    mockRepo.On("GetPurchaseHistory", mock.Anything, "user-id").Return(history, nil).Once()
+
+    result, err := purchasesService.GetPurchaseHistory(context.Background(), "user-id")
+    assert.NoError(t, err)
+    assert.Equal(t, history, result)
+
+    mockRepo.AssertExpectations(t)
+}
+
+func TestBuyMerch_PromotionAndPromoCode(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    promotions := []models.Promotion{
+        {ID: 1, DiscountType: models.DiscountFixed, DiscountValue: 10},
+        {ID: 2, DiscountType: models.DiscountPercent, DiscountValue: 20},
+    }
+    maxUses := 1
+    promoCodeID := 5
+    mockRepo.On("GetMerchId", mock.Anything, "hoody").Return(6, 300, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 6).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 6).Return(promotions, nil).Once()
+    mockRepo.On("GetPromoCode", mock.Anything, "WELCOME").
+        Return(models.PromoCode{ID: promoCodeID, Code: "WELCOME", DiscountType: models.DiscountFixed, DiscountValue: 40, MaxUses: &maxUses}, nil).Once()
+    mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(1000, nil).Once()
+
+    // Из акций выбирается -20% (60 монет), промокод снимает еще 40 с уже сниженной цены
+    promotionID := 2
+    mockRepo.On("BuyMerch", mock.Anything, &models.Purchase{UserID: "user-id", MerchID: 6, Quantity: 1, UnitPrice: 200,
+        Discount: 100, PromotionID: &promotionID, PromoCodeID: &promoCodeID}).Return(nil).Once()
+
+    _, err := purchasesService.BuyMerch(context.Background(), "user-id", "hoody", BuyOptions{PromoCode: "WELCOME"})
+    assert.NoError(t, err)
+
+    mockRepo.AssertExpectations(t)
+}
+
+func TestBuyMerch_PromoCodeUsedUp(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    maxUses := 1
+    mockRepo.On("GetMerchId", mock.Anything, "cup").Return(2, 20, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 2).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 2).Return(nil, nil).Once()
+    mockRepo.On("GetPromoCode", mock.Anything, "ONCE").
+        Return(models.PromoCode{ID: 1, Code: "ONCE", DiscountType: models.DiscountPercent, DiscountValue: 50, MaxUses: &maxUses, UsedCount: 1}, nil).Once()
+
+    _, err := purchasesService.BuyMerch(context.Background(), "user-id", "cup", BuyOptions{PromoCode: "ONCE"})
+    assert.ErrorIs(t, err, repository.ErrPromoCodeInvalid)
+    mockRepo.AssertNotCalled(t, "BuyMerch", mock.Anything, mock.Anything)
+}
+
+func TestDiscountAmount(t *testing.T) {
+    assert.Equal(t, 30, models.DiscountAmount(models.DiscountPercent, 10, 300))
+    assert.Equal(t, 50, models.DiscountAmount(models.DiscountFixed, 50, 300))
+    assert.Equal(t, 20, models.DiscountAmount(models.DiscountFixed, 50, 20))
+    assert.Equal(t, 0, models.DiscountAmount("unknown", 50, 20))
+}
+
+func TestBuyMerch_PurchaseLimitExceeded(t *testing.T) {
+    mockRepo := new(MockPurchasesRepo)
+    mockUserRepo := new(MockUserRepo)
+    purchasesService := NewPurchasesService(mockRepo, mockUserRepo, &config.Config{})
+
+    mockRepo.On("GetMerchId", mock.Anything, "pink-hoody").Return(10, 500, nil).Once()
+    mockRepo.On("CountVariants", mock.Anything, 10).Return(0, nil).Once()
+    mockRepo.On("GetActivePromotions", mock.Anything, 10).Return(nil, nil).Once()
+    mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(1000, nil).Once()
+    mockRepo.On("BuyMerch", mock.Anything, mock.Anything).Return(repository.ErrPurchaseLimitExceeded).Once()
+
+    _, err := purchasesService.BuyMerch(context.Background(), "user-id", "pink-hoody", BuyOptions{})
+    assert.ErrorIs(t, err, repository.ErrPurchaseLimitExceeded)
+}
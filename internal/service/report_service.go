@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+)
+
+// ReportService - сверка балансов и отчеты для эксплуатации.
+type ReportService struct {
+	ReportRepo repository.ReportRepositoryInterface
+}
+
+func NewReportService(reportRepo repository.ReportRepositoryInterface) *ReportService {
+	return &ReportService{ReportRepo: reportRepo}
+}
+
+// ReconcileBalances возвращает пользователей, чей баланс не равен стартовому плюс движения в ledger.
+func (rs *ReportService) ReconcileBalances(ctx context.Context) ([]models.BalanceMismatch, error) {
+	mismatches, err := rs.ReportRepo.ReconcileBalances(ctx, InitialBalance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile balances: %w", err)
+	}
+	return mismatches, nil
+}
+
+func (rs *ReportService) GetBalances(ctx context.Context) ([]models.UserBalance, error) {
+	balances, err := rs.ReportRepo.GetBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+	return balances, nil
+}
+
+// GetPurchases возвращает покупки за [from, to).
+func (rs *ReportService) GetPurchases(ctx context.Context, from, to time.Time) ([]models.PurchaseReportRow, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("report period end must be after its start")
+	}
+
+	purchases, err := rs.ReportRepo.GetPurchases(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchases: %w", err)
+	}
+	return purchases, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"EmployeeMerchStore/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportRepo struct {
+	mock.Mock
+}
+
+func (m *MockReportRepo) ReconcileBalances(ctx context.Context, initialBalance int) ([]models.BalanceMismatch, error) {
+	args := m.Called(ctx, initialBalance)
+	return args.Get(0).([]models.BalanceMismatch), args.Error(1)
+}
+
+func (m *MockReportRepo) GetBalances(ctx context.Context) ([]models.UserBalance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.UserBalance), args.Error(1)
+}
+
+func (m *MockReportRepo) GetPurchases(ctx context.Context, from, to time.Time) ([]models.PurchaseReportRow, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]models.PurchaseReportRow), args.Error(1)
+}
+
+func TestReconcileBalances_UsesInitialBalance(t *testing.T) {
+	mockRepo := new(MockReportRepo)
+	reportService := NewReportService(mockRepo)
+
+	mismatch := models.BalanceMismatch{UserID: "user-id-1", Username: "alice", Balance: 900, Expected: 950}
+	mockRepo.On("ReconcileBalances", mock.Anything, InitialBalance).Return([]models.BalanceMismatch{mismatch}, nil).Once()
+
+	mismatches, err := reportService.ReconcileBalances(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.BalanceMismatch{mismatch}, mismatches)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetPurchasesReport_InvalidPeriod(t *testing.T) {
+	mockRepo := new(MockReportRepo)
+	reportService := NewReportService(mockRepo)
+
+	now := time.Now()
+	_, err := reportService.GetPurchases(context.Background(), now, now.Add(-time.Hour))
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetPurchases", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -6,8 +6,10 @@ import (
     "time"
 
 	"EmployeeMerchStore/internal/repository"
+	"EmployeeMerchStore/internal/tracing"
 	"EmployeeMerchStore/internal/models"
     "EmployeeMerchStore/internal/cache"
+    "EmployeeMerchStore/internal/metrics"
 	"EmployeeMerchStore/config"
 
 	"github.com/google/uuid"
@@ -15,6 +17,9 @@ import (
     "golang.org/x/crypto/bcrypt"
 )
 
+// InitialBalance - стартовый баланс нового пользователя (по тз)
+const InitialBalance = 1000
+
 type UserService struct {
     config   *config.Config
     userRepo repository.UserRepositoryInterface
@@ -34,6 +39,16 @@ func NewUserService(userRepo repository.UserRepositoryInterface, config *config.
     }
 }
 
+// Close останавливает фоновую очистку кэша токенов.
+func (us *UserService) Close() {
+    us.cache.Stop()
+}
+
+// CacheStats возвращает попадания и промахи кэша токенов для метрик.
+func (us *UserService) CacheStats() (hits, misses uint64) {
+    return us.cache.Stats()
+}
+
 func (us *UserService) CreateUser(ctx context.Context, username, password string) (string, error) {
     id := uuid.New().String()
 
@@ -43,8 +58,8 @@ func (us *UserService) CreateUser(ctx context.Context, username, password string
         return "", fmt.Errorf("failed to hash password: %w", err)
     }
 
-    // Создаём пользователя с балансом 1000 (по тз)
-    if err := us.userRepo.CreateUser(ctx, id, username, hashPswd, 1000); err != nil {
+    // Создаём пользователя со стартовым балансом
+    if err := us.userRepo.CreateUser(ctx, id, username, hashPswd, InitialBalance); err != nil {
         return "", fmt.Errorf("failed to create user: %w", err)
     }
 
@@ -61,11 +76,9 @@ func (us *UserService) CreateUser(ctx context.Context, username, password string
     return token, nil
 }
 
-func (us *UserService) GetInfo(ctx context.Context, token string, ps *PurchasesService, ls *LedgerService) (int, []*models.UserMerch, []*models.Ledger, []*models.Ledger, error) {
-    userID, err := us.DecodeToken(token)
-    if err != nil {
-        return 0, nil, nil, nil, fmt.Errorf("failed to decode auth token: %w", err)
-    }
+func (us *UserService) GetInfo(ctx context.Context, userID string, ps *PurchasesService, ls *LedgerService) (int, []*models.UserMerch, []*models.Ledger, []*models.Ledger, error) {
+    ctx, span := tracing.Start(ctx, "UserService.GetInfo")
+    defer span.End()
 
     balance, err := us.GetBalance(ctx, userID)
     if err != nil {
@@ -87,6 +100,9 @@ func (us *UserService) GetInfo(ctx context.Context, token string, ps *PurchasesS
 
 
 func (us *UserService) GetBalance(ctx context.Context, id string) (int, error) {
+    ctx, span := tracing.Start(ctx, "UserService.GetBalance")
+    defer span.End()
+
     balance, err := us.userRepo.GetBalance(ctx, id)
 
 	if err != nil {
@@ -96,7 +112,31 @@ func (us *UserService) GetBalance(ctx context.Context, id string) (int, error) {
 	return balance, nil
 }
 
+// SetAdmin выдает или забирает права администратора.
+func (us *UserService) SetAdmin(ctx context.Context, username string, admin bool) error {
+    id, _, err := us.userRepo.GetUserCredentials(ctx, username)
+    if err != nil {
+        return fmt.Errorf("failed to get user '%s': %w", username, err)
+    }
+
+    if err := us.userRepo.SetAdmin(ctx, id, admin); err != nil {
+        return fmt.Errorf("failed to set admin for '%s': %w", username, err)
+    }
+    return nil
+}
+
+func (us *UserService) IsAdmin(ctx context.Context, id string) (bool, error) {
+    isAdmin, err := us.userRepo.IsAdmin(ctx, id)
+    if err != nil {
+        return false, fmt.Errorf("failed to check admin rights: %w", err)
+    }
+    return isAdmin, nil
+}
+
 func (us *UserService) Auth(ctx context.Context, username, password string) (string, error) {
+    ctx, span := tracing.Start(ctx, "UserService.Auth")
+    defer span.End()
+
     // Проверяем кэш 
     cacheKey := "auth:" + username + ":" + password
     if tokenCached, found := us.cache.Get(cacheKey); found {
@@ -112,6 +152,7 @@ func (us *UserService) Auth(ctx context.Context, username, password string) (str
     
     // Сравниваем хэш с предоставленным паролем
     if err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(password)); err != nil {
+        metrics.FailedAuths.WithLabelValues("wrong_password").Inc()
         return "", fmt.Errorf("invalid credentials: %w", err)
     }
     
@@ -133,6 +174,7 @@ func (us *UserService) DecodeToken(tokenStr string) (string, error) {
         return jwtKey, nil
     })
     if err != nil || !token.Valid {
+        metrics.FailedAuths.WithLabelValues("invalid_token").Inc()
         return "", fmt.Errorf("invalid token")
     }
     return claims.UserID, nil
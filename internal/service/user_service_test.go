@@ -30,6 +30,16 @@ func (m *MockUserRepo) CreateUser(ctx context.Context, id, username, hashPswd st
     return args.Error(0)
 }
 
+func (m *MockUserRepo) IsAdmin(ctx context.Context, id string) (bool, error) {
+    args := m.Called(ctx, id)
+    return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) SetAdmin(ctx context.Context, id string, admin bool) error {
+    args := m.Called(ctx, id, admin)
+    return args.Error(0)
+}
+
 func TestCreateUser(t *testing.T) {
     mockRepo := &MockUserRepo{}
     cfg := &config.Config{}
@@ -114,3 +124,30 @@ func TestGenerateJWT_Success(t *testing.T) {
     assert.NotEmpty(t, token)
 }
 
+func TestIsAdmin(t *testing.T) {
+    mockRepo := &MockUserRepo{}
+    cfg := &config.Config{}
+    userService := NewUserService(mockRepo, cfg)
+
+    mockRepo.On("IsAdmin", mock.Anything, "admin-id").Return(true, nil)
+    mockRepo.On("IsAdmin", mock.Anything, "user-id").Return(false, nil)
+
+    isAdmin, err := userService.IsAdmin(context.Background(), "admin-id")
+    assert.NoError(t, err)
+    assert.True(t, isAdmin)
+
+    isAdmin, err = userService.IsAdmin(context.Background(), "user-id")
+    assert.NoError(t, err)
+    assert.False(t, isAdmin)
+}
+
+func TestSetAdmin(t *testing.T) {
+    mockRepo := &MockUserRepo{}
+    userService := NewUserService(mockRepo, &config.Config{})
+
+    mockRepo.On("GetUserCredentials", mock.Anything, "alice").Return("user-id-1", "hash", nil).Once()
+    mockRepo.On("SetAdmin", mock.Anything, "user-id-1", true).Return(nil).Once()
+
+    assert.NoError(t, userService.SetAdmin(context.Background(), "alice", true))
+    mockRepo.AssertExpectations(t)
+}
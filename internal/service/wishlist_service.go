@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"EmployeeMerchStore/internal/models"
+	"EmployeeMerchStore/internal/repository"
+)
+
+type WishlistService struct {
+	WishlistRepo  repository.WishlistRepositoryInterface
+	PurchasesRepo repository.PurchasesRepositoryInterface
+	UserRepo      repository.UserRepositoryInterface
+}
+
+func NewWishlistService(wishlistRepo repository.WishlistRepositoryInterface, purchasesRepo repository.PurchasesRepositoryInterface, userRepo repository.UserRepositoryInterface) *WishlistService {
+	return &WishlistService{
+		WishlistRepo:  wishlistRepo,
+		PurchasesRepo: purchasesRepo,
+		UserRepo:      userRepo,
+	}
+}
+
+func (ws *WishlistService) AddToWishlist(ctx context.Context, userID, nameMerch string) error {
+	merchID, _, err := ws.PurchasesRepo.GetMerchId(ctx, nameMerch)
+	if err != nil {
+		return fmt.Errorf("failed to get merch id for '%s': %w", nameMerch, err)
+	}
+
+	if err := ws.WishlistRepo.AddToWishlist(ctx, userID, merchID); err != nil {
+		return fmt.Errorf("failed to add to wishlist: %w", err)
+	}
+	return nil
+}
+
+func (ws *WishlistService) RemoveFromWishlist(ctx context.Context, userID, nameMerch string) error {
+	merchID, _, err := ws.PurchasesRepo.GetMerchId(ctx, nameMerch)
+	if err != nil {
+		return fmt.Errorf("failed to get merch id for '%s': %w", nameMerch, err)
+	}
+
+	if err := ws.WishlistRepo.RemoveFromWishlist(ctx, userID, merchID); err != nil {
+		return fmt.Errorf("failed to remove from wishlist: %w", err)
+	}
+	return nil
+}
+
+// GetWishlist возвращает текущий баланс и вишлист с прогрессом накопления на каждый товар.
+func (ws *WishlistService) GetWishlist(ctx context.Context, userID string) (int, []models.WishlistItem, error) {
+	balance, err := ws.UserRepo.GetBalance(ctx, userID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	items, err := ws.WishlistRepo.GetWishlist(ctx, userID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	for i := range items {
+		fillProgress(&items[i], balance)
+	}
+
+	return balance, items, nil
+}
+
+func (ws *WishlistService) GetNotifications(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error) {
+	notifications, err := ws.WishlistRepo.GetNotifications(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (ws *WishlistService) MarkNotificationRead(ctx context.Context, userID string, id int) error {
+	if err := ws.WishlistRepo.MarkNotificationRead(ctx, userID, id); err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	return nil
+}
+
+// fillProgress считает, сколько накоплено на товар при текущем балансе.
+func fillProgress(item *models.WishlistItem, balance int) {
+	if balance < 0 {
+		balance = 0
+	}
+
+	item.Affordable = balance >= item.Price
+	if item.Affordable {
+		item.Progress = 100
+		item.Missing = 0
+		return
+	}
+
+	item.Missing = item.Price - balance
+	item.Progress = balance * 100 / item.Price
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"EmployeeMerchStore/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWishlistRepo struct {
+	mock.Mock
+}
+
+func (m *MockWishlistRepo) AddToWishlist(ctx context.Context, userID string, merchID int) error {
+	args := m.Called(ctx, userID, merchID)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepo) RemoveFromWishlist(ctx context.Context, userID string, merchID int) error {
+	args := m.Called(ctx, userID, merchID)
+	return args.Error(0)
+}
+
+func (m *MockWishlistRepo) GetWishlist(ctx context.Context, userID string) ([]models.WishlistItem, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WishlistItem), args.Error(1)
+}
+
+func (m *MockWishlistRepo) NotifyWishlisters(ctx context.Context, merchID int, kind, message string) (int, error) {
+	args := m.Called(ctx, merchID, kind, message)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWishlistRepo) GetNotifications(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockWishlistRepo) MarkNotificationRead(ctx context.Context, userID string, id int) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+func TestGetWishlist_Progress(t *testing.T) {
+	mockWishlistRepo := new(MockWishlistRepo)
+	mockUserRepo := new(MockUserRepo)
+	wishlistService := NewWishlistService(mockWishlistRepo, nil, mockUserRepo)
+
+	mockUserRepo.On("GetBalance", mock.Anything, "user-id").Return(250, nil).Once()
+	mockWishlistRepo.On("GetWishlist", mock.Anything, "user-id").Return([]models.WishlistItem{
+		{MerchID: 10, Name: "pink-hoody", Price: 500},
+		{MerchID: 1, Name: "T-Shirt", Price: 80},
+	}, nil).Once()
+
+	balance, items, err := wishlistService.GetWishlist(context.Background(), "user-id")
+	assert.NoError(t, err)
+	assert.Equal(t, 250, balance)
+
+	assert.Equal(t, 50, items[0].Progress)
+	assert.Equal(t, 250, items[0].Missing)
+	assert.False(t, items[0].Affordable)
+
+	assert.Equal(t, 100, items[1].Progress)
+	assert.Equal(t, 0, items[1].Missing)
+	assert.True(t, items[1].Affordable)
+}
+
+func TestAddToWishlist_Success(t *testing.T) {
+	mockWishlistRepo := new(MockWishlistRepo)
+	mockPurchasesRepo := new(MockPurchasesRepo)
+	wishlistService := NewWishlistService(mockWishlistRepo, mockPurchasesRepo, nil)
+
+	mockPurchasesRepo.On("GetMerchId", mock.Anything, "pink-hoody").Return(10, 500, nil).Once()
+	mockWishlistRepo.On("AddToWishlist", mock.Anything, "user-id", 10).Return(nil).Once()
+
+	err := wishlistService.AddToWishlist(context.Background(), "user-id", "pink-hoody")
+	assert.NoError(t, err)
+
+	mockWishlistRepo.AssertExpectations(t)
+	mockPurchasesRepo.AssertExpectations(t)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultLocalDir     = "media"
+	defaultLocalBaseURL = "/media"
+)
+
+// LocalStorage хранит файлы в каталоге на диске и сам раздает их по HTTP.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	if dir == "" {
+		dir = defaultLocalDir
+	}
+	if baseURL == "" {
+		baseURL = defaultLocalBaseURL
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage dir: %w", err)
+	}
+	return &LocalStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (ls *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create dir for %s: %w", key, err)
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы не отдавать недописанный файл
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file for %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
+	return nil
+}
+
+func (ls *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (ls *LocalStorage) URL(key string) string {
+	return ls.baseURL + "/" + key
+}
+
+// BaseURL - префикс, по которому хранилище нужно смонтировать в роутере.
+func (ls *LocalStorage) BaseURL() string {
+	return ls.baseURL
+}
+
+// ServeHTTP раздает сохраненные файлы. Путь запроса должен быть уже без BaseURL.
+func (ls *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	http.FileServer(http.Dir(ls.dir)).ServeHTTP(w, r)
+}
+
+// path переводит ключ в путь на диске. Абсолютные ключи и ключи с ".." отклоняются,
+// чтобы нельзя было выйти за пределы каталога хранилища.
+func (ls *LocalStorage) path(key string) (string, error) {
+	slashed := filepath.ToSlash(key)
+	if slashed == "" || strings.HasPrefix(slashed, "/") || filepath.IsAbs(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(slashed, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+
+	clean := filepath.Clean(key)
+	if clean == "." {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(ls.dir, clean), nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutURLDelete(t *testing.T) {
+	dir := t.TempDir()
+	ls, err := NewLocalStorage(dir, "/media/")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, ls.Put(ctx, "merch/1/a.png", strings.NewReader("png"), 3, "image/png"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "merch", "1", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+	assert.Equal(t, "/media/merch/1/a.png", ls.URL("merch/1/a.png"))
+	assert.Equal(t, "/media", ls.BaseURL())
+
+	// Повторный Put перезаписывает файл целиком
+	require.NoError(t, ls.Put(ctx, "merch/1/a.png", strings.NewReader("new"), 3, "image/png"))
+	data, err = os.ReadFile(filepath.Join(dir, "merch", "1", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	require.NoError(t, ls.Delete(ctx, "merch/1/a.png"))
+	_, err = os.Stat(filepath.Join(dir, "merch", "1", "a.png"))
+	assert.True(t, os.IsNotExist(err))
+	assert.ErrorIs(t, ls.Delete(ctx, "merch/1/a.png"), ErrNotFound)
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	dir := t.TempDir()
+	ls, err := NewLocalStorage(filepath.Join(dir, "media"), "")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, key := range []string{"", ".", "../secret", "merch/../../secret", "merch/..", "/etc/passwd", "/merch/1/a.png"} {
+		_, err := ls.path(key)
+		assert.Error(t, err, key)
+		assert.Error(t, ls.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"), key)
+		assert.Error(t, ls.Delete(ctx, key), key)
+	}
+
+	// Ничего не записалось рядом с каталогом хранилища
+	_, err = os.Stat(filepath.Join(dir, "secret"))
+	assert.True(t, os.IsNotExist(err))
+
+	path, err := ls.path("merch/1/a.png")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "media", "merch", "1", "a.png"), path)
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"EmployeeMerchStore/config"
+)
+
+// ErrNotFound возвращается, если объекта с таким ключом нет.
+var ErrNotFound = errors.New("object not found")
+
+// Storage - хранилище файлов (картинок мерча). Ключ - относительный путь вида merch/1/abc.jpg.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL возвращает адрес, по которому клиент может скачать объект.
+	URL(key string) string
+}
+
+// New создает хранилище по конфигу. По умолчанию файлы лежат на локальном диске.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.Local.Dir, cfg.Local.BaseURL)
+	case "s3":
+		return NewS3Storage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
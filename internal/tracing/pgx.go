@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PgxLogLevel - уровень логов pgx, нужный трассировке: о каждом запросе pgx
+// сообщает на info. Когда трассировка выключена, логи ей не нужны.
+func PgxLogLevel() pgx.LogLevel {
+	if enabled {
+		return pgx.LogLevelInfo
+	}
+	return pgx.LogLevelNone
+}
+
+// PgxLogger превращает записи pgx о запросах в спаны и передает записи дальше в next.
+// В pgx v4 нет хуков трассировки, но запись приходит сразу после запроса вместе с
+// его длительностью, так что спан восстанавливается задним числом. Спан создается,
+// только если в ctx уже есть родитель - запросы вне HTTP-запросов и задач не трассируются.
+func PgxLogger(next pgx.Logger) pgx.Logger {
+	return pgx.LoggerFunc(func(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+		if enabled && trace.SpanContextFromContext(ctx).IsValid() {
+			recordQuery(ctx, msg, data)
+		}
+		next.Log(ctx, level, msg, data)
+	})
+}
+
+func recordQuery(ctx context.Context, msg string, data map[string]interface{}) {
+	switch msg {
+	case "Query", "Exec", "SendBatch", "CopyFrom":
+	default:
+		return
+	}
+	duration, ok := data["time"].(time.Duration)
+	if !ok {
+		return
+	}
+
+	end := time.Now()
+	_, span := Start(ctx, "db."+strings.ToLower(msg),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(end.Add(-duration)),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	if sql, ok := data["sql"].(string); ok {
+		span.SetAttributes(semconv.DBStatement(sql))
+	}
+	if err, ok := data["err"].(error); ok && err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if rows, ok := data["rowCount"].(int64); ok {
+		span.SetAttributes(attribute.Int64("db.rows", rows))
+	}
+	span.End(trace.WithTimestamp(end))
+}
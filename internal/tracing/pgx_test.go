@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	enabled = true
+	t.Cleanup(func() { enabled = false })
+	return recorder
+}
+
+func TestPgxLogger_RecordsQuerySpan(t *testing.T) {
+	recorder := setupRecorder(t)
+	var passed bool
+	logger := PgxLogger(pgx.LoggerFunc(func(context.Context, pgx.LogLevel, string, map[string]interface{}) {
+		passed = true
+	}))
+
+	ctx, parent := Start(context.Background(), "UserService.GetBalance")
+	logger.Log(ctx, pgx.LogLevelInfo, "Query", map[string]interface{}{
+		"sql":      `SELECT balance FROM "MerchStore".users WHERE id = $1`,
+		"time":     20 * time.Millisecond,
+		"rowCount": int64(1),
+	})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, "db.query", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, 20*time.Millisecond, query.EndTime().Sub(query.StartTime()))
+	assert.True(t, passed, "record must be passed to the next logger")
+}
+
+func TestPgxLogger_MarksFailedQuery(t *testing.T) {
+	recorder := setupRecorder(t)
+	logger := PgxLogger(pgx.LoggerFunc(func(context.Context, pgx.LogLevel, string, map[string]interface{}) {}))
+
+	ctx, parent := Start(context.Background(), "LedgerService.SendMoney")
+	logger.Log(ctx, pgx.LogLevelError, "Exec", map[string]interface{}{
+		"sql":  `UPDATE "MerchStore".users SET balance = balance - $1 WHERE id = $2`,
+		"time": time.Millisecond,
+		"err":  errors.New("deadlock detected"),
+	})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestPgxLogger_SkipsQueriesWithoutParent(t *testing.T) {
+	recorder := setupRecorder(t)
+	logger := PgxLogger(pgx.LoggerFunc(func(context.Context, pgx.LogLevel, string, map[string]interface{}) {}))
+
+	logger.Log(context.Background(), pgx.LogLevelInfo, "Query", map[string]interface{}{"time": time.Millisecond})
+
+	assert.Empty(t, recorder.Ended())
+}
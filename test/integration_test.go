@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -12,44 +13,72 @@ import (
 	"EmployeeMerchStore/api"
 	"EmployeeMerchStore/config"
 	"EmployeeMerchStore/internal/database"
+	"EmployeeMerchStore/internal/logging"
 	"EmployeeMerchStore/internal/models"
 	"EmployeeMerchStore/internal/repository"
 	"EmployeeMerchStore/internal/service"
+	"EmployeeMerchStore/internal/storage"
 	"log"
 )
 
 // CreateTestHandler инициализирует тестовый хэндлер с !!!!реальной БД!!!!
 // БД надо запустить и созать там базу данных из config/config.yml
 func CreateTestHandler() *api.Handler {
-	// Загружаем конфиг 
-	cfg, err := config.LoadConfig("config/config.yml")
+	// Загружаем конфиг: go test запускает тесты из каталога пакета
+	cfg, err := config.LoadConfig("../config/config.yml")
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
 
 	// Инициализируем базу данных
-	dbPool, err := database.InitDB(cfg)
+	logger := logging.Discard()
+	dbPool, err := database.InitDB(cfg, logger)
 	if err != nil {
 		log.Fatalf("Database connection failed: %v", err)
 	}
 	// Запускаем миграции
-	err = database.RunMigrations(context.Background(), dbPool)
+	err = database.RunMigrations(context.Background(), dbPool, logger)
 	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
+	// Тесты покупают мерч из стартового каталога
+	err = database.Seed(context.Background(), dbPool, logger)
+	if err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
 
 	// Создаем репозитории
 	userRepo := repository.NewUserRepository(dbPool)
 	purchasesRepo := repository.NewPurchasesRepository(dbPool)
 	ledgerRepo := repository.NewLedgerRepository(dbPool)
+	merchRepo := repository.NewMerchRepository(dbPool)
+	wishlistRepo := repository.NewWishlistRepository(dbPool)
+	orderRepo := repository.NewOrderRepository(dbPool)
+	promotionRepo := repository.NewPromotionRepository(dbPool)
+
+	// Хранилище картинок мерча
+	mediaStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Storage init failed: %v", err)
+	}
 
 	// Создаем сервисы
 	userService := service.NewUserService(userRepo, cfg)
-	purchasesService := service.NewPurchasesService(purchasesRepo, userRepo)
-	ledgerService := service.NewLedgerService(ledgerRepo, userRepo)
+	purchasesService := service.NewPurchasesService(purchasesRepo, userRepo, cfg)
+	ledgerService := service.NewLedgerService(ledgerRepo, userRepo, cfg, logger)
+	merchService := service.NewMerchService(merchRepo, wishlistRepo, logger)
+	wishlistService := service.NewWishlistService(wishlistRepo, purchasesRepo, userRepo)
+	orderService := service.NewOrderService(orderRepo, cfg)
+	promotionService := service.NewPromotionService(promotionRepo)
+	imageService := service.NewImageService(merchRepo, mediaStorage, cfg, logger)
+	migrator, err := database.NewMigrator(dbPool, database.Migrations(), logger)
+	if err != nil {
+		log.Fatalf("Migrator init failed: %v", err)
+	}
+	healthService := service.NewHealthService(dbPool, migrator)
 
 	// Создаем и возвращаем хэндлер
-	return api.NewHandler(userService, purchasesService, ledgerService)
+	return api.NewHandler(userService, purchasesService, ledgerService, merchService, wishlistService, orderService, promotionService, imageService, healthService, logger)
 }
 
 func TestAuthEndpoint(t *testing.T) {
@@ -248,4 +277,102 @@ func TestBuyMerchEndpoint(t *testing.T) {
 		body, _ := ioutil.ReadAll(buyResp.Body)
 		t.Fatalf("Expected status 200, got %d: %s", buyResp.StatusCode, string(body))
 	}
-}
\ No newline at end of file
+}
+// TestReturnCancelsOpenOrder проверяет, что после возврата заказ нельзя собрать и выдать:
+// возврат отменяет еще не выданный заказ.
+func TestReturnCancelsOpenOrder(t *testing.T) {
+	handler := CreateTestHandler()
+	server := httptest.NewServer(api.RegisterRoutes(handler))
+	defer server.Close()
+
+	payload := map[string]string{
+		"username": "returneruser",
+		"password": "returnerpass",
+	}
+	data, _ := json.Marshal(payload)
+	resp, err := http.Post(server.URL+"/api/auth", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to create returner user: %v", err)
+	}
+	defer resp.Body.Close()
+	var authResp struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		t.Fatalf("Failed to decode auth response: %v", err)
+	}
+	if authResp.Token == "" {
+		t.Fatalf("Returner token is empty")
+	}
+	// Тот же пользователь двигает заказ как администратор
+	if err := handler.UserService.SetAdmin(context.Background(), "returneruser", true); err != nil {
+		t.Fatalf("Failed to grant admin rights: %v", err)
+	}
+
+	do := func(method, path string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create %s %s request: %v", method, path, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+authResp.Token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		return resp
+	}
+
+	buyResp := do("GET", "/api/buy/T-Shirt", nil)
+	defer buyResp.Body.Close()
+	if buyResp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(buyResp.Body)
+		t.Fatalf("Expected status 200 on buy, got %d: %s", buyResp.StatusCode, string(body))
+	}
+	var bought struct {
+		OrderID int `json:"orderId"`
+	}
+	if err := json.NewDecoder(buyResp.Body).Decode(&bought); err != nil {
+		t.Fatalf("Failed to decode buy response: %v", err)
+	}
+
+	returnResp := do("POST", "/api/return/T-Shirt", nil)
+	defer returnResp.Body.Close()
+	if returnResp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(returnResp.Body)
+		t.Fatalf("Expected status 200 on return, got %d: %s", returnResp.StatusCode, string(body))
+	}
+
+	for _, status := range []string{models.OrderPacked, models.OrderCancelled} {
+		data, _ := json.Marshal(map[string]string{"status": status})
+		resp := do("PUT", fmt.Sprintf("/api/admin/orders/%d/status", bought.OrderID), data)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("Expected 409 moving returned order to %q, got %d", status, resp.StatusCode)
+		}
+	}
+}
+
+func TestReadyzEndpoint(t *testing.T) {
+	handler := CreateTestHandler()
+	server := httptest.NewServer(api.RegisterRoutes(handler))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 OK, got %d", resp.StatusCode)
+	}
+
+	handler.HealthService.SetShuttingDown()
+	resp, err = http.Get(server.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 while shutting down, got %d", resp.StatusCode)
+	}
+}